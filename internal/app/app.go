@@ -13,6 +13,7 @@ import (
 	"news-scrabber/internal/storage/s3client"
 	"news-scrabber/internal/transcribe"
 	"news-scrabber/internal/transcribe/whisper"
+	"news-scrabber/internal/translate"
 	"news-scrabber/internal/vector/qdrant"
 
 	"go.uber.org/fx"
@@ -25,6 +26,8 @@ func Serve() *fx.App {
 		fx.Module("bootstrap",
 			fx.Provide(config.LoadConfig),
 			fx.Provide(bootstrap.NewLogger),
+			fx.Provide(bootstrap.NewInstanceID),
+			fx.Provide(bootstrap.NewStatsd),
 		),
 
 		fx.Module("infra",
@@ -35,19 +38,32 @@ func Serve() *fx.App {
 			fx.Provide(s3client.New),
 			fx.Provide(qdrant.NewClient),
 			fx.Provide(elasticsearch.NewClient), // Elasticsearch HTTP client
-			fx.Provide(whisper.NewClient),       // Faster-Whisper HTTP client
+			fx.Provide(whisper.NewTranscriber),  // Whisper backend chain (REST, OpenAI, whisper.cpp)
+			fx.Provide(translate.NewClient),     // OpenAI-compatible translation client
 		),
 
 		fx.Module("http",
 			fx.Provide(transribe.NewRequestTranscribeAction),
+			fx.Provide(transribe.NewGetTranscribeStatusAction),
+			fx.Provide(transribe.NewCancelTranscribeAction),
+			fx.Provide(transribe.NewListDeadLettersAction),
+			fx.Provide(transribe.NewReplayDeadLetterAction),
 			fx.Provide(server.NewFiberApp),
 			fx.Invoke(server.Start),
 		),
 
 		fx.Module("domain",
 			fx.Provide(scraper.NewService),
+			fx.Provide(transcribe.NewStatusRegistry),
+			fx.Provide(transcribe.NewCheckpointStore),
+			fx.Provide(transcribe.NewDiskGuard),
+			fx.Provide(transcribe.NewResolverChain),
+			fx.Provide(transcribe.NewIndexRouter),
+			fx.Provide(transcribe.NewWhisperScheduler),
 			fx.Provide(transcribe.NewService),
 			fx.Provide(transcribe.NewPublisher),
+			fx.Provide(transcribe.NewDeadLetterStore),
+			fx.Provide(transcribe.NewLeaseManager),
 			fx.Provide(transcribe.NewDispatcher),
 			fx.Provide(enrich.NewService),
 		),
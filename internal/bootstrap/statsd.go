@@ -2,13 +2,27 @@ package bootstrap
 
 import (
 	"context"
+	"strings"
+
+	"news-scrabber/internal/config"
 
 	"github.com/DataDog/datadog-go/v5/statsd"
 	"go.uber.org/fx"
 )
 
-func NewStatsd(lc fx.Lifecycle) (*statsd.Client, error) {
-	stat, err := statsd.New("")
+// NewStatsd returns a DogStatsD client for DD_DOGSTATSD_HOST; without a host metrics are dropped.
+func NewStatsd(lc fx.Lifecycle, cfg *config.Config) (statsd.ClientInterface, error) {
+	if cfg.DatadogHost == "" {
+		return &statsd.NoOpClient{}, nil
+	}
+	addr := cfg.DatadogHost
+	if !strings.Contains(addr, ":") {
+		addr += ":8125"
+	}
+	stat, err := statsd.New(addr, statsd.WithTags([]string{"app:" + cfg.AppName()}))
+	if err != nil {
+		return nil, err
+	}
 
 	lc.Append(fx.Hook{
 		OnStop: func(_ context.Context) error {
@@ -16,5 +30,5 @@ func NewStatsd(lc fx.Lifecycle) (*statsd.Client, error) {
 		},
 	})
 
-	return stat, err
+	return stat, nil
 }
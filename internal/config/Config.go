@@ -16,6 +16,7 @@ type Config struct {
 	OpenAI       OpenAIConfig       `envPrefix:"OPENAI_"`
 	Whisper      WhisperConfig      `envPrefix:"WHISPER_"`
 	Transcribe   TranscribeConfig   `envPrefix:"TRANSCRIBE_"`
+	Translate    TranslateConfig    `envPrefix:"TRANSLATE_"`
 	Scraper      ScraperConfig      `envPrefix:"SCRAPER_"`
 
 	MaxPublishRetryAttempts uint8 `env:"MAX_PUBLISH_RETRY_ATTEMPTS" envDefault:"10"`
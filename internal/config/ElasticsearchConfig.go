@@ -7,4 +7,7 @@ type ElasticsearchConfig struct {
 	Username    string `env:"USERNAME"`
 	Password    string `env:"PASSWORD"`
 	IndexPrefix string `env:"INDEX_PREFIX" envDefault:"news"`
+	// LanguageAnalyzers maps a language code to the analyzer of its dedicated transcript index,
+	// e.g. "en:english,ru:russian". "ukrainian" needs the analysis-ukrainian plugin.
+	LanguageAnalyzers map[string]string `env:"LANGUAGE_ANALYZERS" envSeparator:"," envKeyValSeparator:":" envDefault:"en:english,ru:russian,uk:ukrainian"`
 }
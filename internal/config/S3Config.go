@@ -7,4 +7,6 @@ type S3Config struct {
 	AccessKey string `env:"ACCESS_KEY"`
 	SecretKey string `env:"SECRET_KEY"`
 	UseSSL    bool   `env:"USE_SSL" envDefault:"false"`
+	// TimeoutSeconds bounds each request, including sending a multipart part and reading a Get body.
+	TimeoutSeconds int `env:"TIMEOUT_SECONDS" envDefault:"120"`
 }
@@ -1,8 +1,71 @@
 package config
 
 type TranscribeConfig struct {
-	FFmpegPath   string `env:"FFMPEG_PATH" envDefault:"ffmpeg"`
-	TempDir      string `env:"TEMP_DIR" envDefault:"/tmp/news-scrabber"`
+	FFmpegPath    string `env:"FFMPEG_PATH" envDefault:"ffmpeg"`
+	TempDir       string `env:"TEMP_DIR" envDefault:"/tmp/news-scrabber"`
 	MaxConcurrent int    `env:"MAX_CONCURRENT" envDefault:"2"`
 	QueueSize     int    `env:"QUEUE_SIZE" envDefault:"100"`
+	// ChunkParallelism is how many segments of one job are transcribed at once.
+	ChunkParallelism int `env:"CHUNK_PARALLELISM" envDefault:"2"`
+
+	// Defaults for per-request options (see transcribe.JobOptions).
+	SegmentSeconds int    `env:"SEGMENT_SECONDS" envDefault:"60"`
+	WindowSize     int    `env:"WINDOW_SIZE" envDefault:"7"`
+	Language       string `env:"LANGUAGE" envDefault:"auto"`
+	BeamSize       int    `env:"BEAM_SIZE" envDefault:"1"`
+	AudioStream    string `env:"AUDIO_STREAM"`
+	// OverlapSeconds of previous audio prepended to each chunk sent to Whisper; 0 disables overlap mode.
+	OverlapSeconds int `env:"OVERLAP_SECONDS" envDefault:"0"`
+
+	// CutAtSilence cuts segments at pauses within SilenceToleranceSeconds of the segment length
+	// instead of strictly every segment length.
+	CutAtSilence            bool    `env:"CUT_AT_SILENCE" envDefault:"false"`
+	SilenceToleranceSeconds int     `env:"SILENCE_TOLERANCE_SECONDS" envDefault:"10"`
+	SilenceThresholdDB      float64 `env:"SILENCE_THRESHOLD_DB" envDefault:"-40"`
+	SilenceMinPauseMillis   int     `env:"SILENCE_MIN_PAUSE_MILLIS" envDefault:"300"`
+
+	// Live sources are restarted whenever ffmpeg exits, with an error or cleanly (a live
+	// stream that ends has dropped, not finished). The delay starts at
+	// ReconnectBackoffSeconds and doubles up to ReconnectMaxBackoffSeconds; after
+	// ReconnectMaxAttempts consecutive failed restarts the job gives up (0 disables reconnects).
+	ReconnectMaxAttempts       int `env:"RECONNECT_MAX_ATTEMPTS" envDefault:"10"`
+	ReconnectBackoffSeconds    int `env:"RECONNECT_BACKOFF_SECONDS" envDefault:"2"`
+	ReconnectMaxBackoffSeconds int `env:"RECONNECT_MAX_BACKOFF_SECONDS" envDefault:"60"`
+
+	// RetentionSeconds keeps chunk files locally after they were uploaded and indexed:
+	// 0 deletes them right away, -1 keeps them forever.
+	RetentionSeconds int `env:"RETENTION_SECONDS" envDefault:"0"`
+	// FailedRetentionSeconds keeps the files of chunks that failed or missed S3 or
+	// Elasticsearch, in <job>/failed: their audio may exist nowhere else. -1 keeps them forever.
+	FailedRetentionSeconds int `env:"FAILED_RETENTION_SECONDS" envDefault:"86400"`
+	// MinFreeDiskMB is the free space watermark in TempDir below which no new jobs are started (0 disables).
+	MinFreeDiskMB            int `env:"MIN_FREE_DISK_MB" envDefault:"1024"`
+	DiskCheckIntervalSeconds int `env:"DISK_CHECK_INTERVAL_SECONDS" envDefault:"30"`
+
+	// Source resolution before ffmpeg: yt-dlp runs for YtDlpHosts (and their subdomains),
+	// article pages are scanned for an embedded player, HLS masters reduced to one variant.
+	YtDlpPath             string   `env:"YTDLP_PATH" envDefault:"yt-dlp"`
+	YtDlpHosts            []string `env:"YTDLP_HOSTS" envSeparator:"," envDefault:"youtube.com,youtu.be,vimeo.com,twitch.tv,facebook.com,dailymotion.com"`
+	ResolveTimeoutSeconds int      `env:"RESOLVE_TIMEOUT_SECONDS" envDefault:"30"`
+
+	// SourceLanguages pins the language per source host, e.g. "suspilne.media:uk,rt.com:ru".
+	// Requests that set a language override it; other sources use Language.
+	SourceLanguages map[string]string `env:"SOURCE_LANGUAGES" envSeparator:"," envKeyValSeparator:":"`
+
+	// A failed request is redelivered after RedeliveryBackoffSeconds, doubling up to
+	// RedeliveryMaxBackoffSeconds, and dead-lettered after MaxDeliver failed runs (0: never).
+	// Hand-backs on shutdown and lease contention are redelivered without counting.
+	MaxDeliver                  int `env:"MAX_DELIVER" envDefault:"5"`
+	RedeliveryBackoffSeconds    int `env:"REDELIVERY_BACKOFF_SECONDS" envDefault:"10"`
+	RedeliveryMaxBackoffSeconds int `env:"REDELIVERY_MAX_BACKOFF_SECONDS" envDefault:"600"`
+
+	// ShutdownGraceSeconds lets running jobs finish and checkpoint their in-flight chunks
+	// on shutdown before they are cancelled and handed back to the queue.
+	ShutdownGraceSeconds int `env:"SHUTDOWN_GRACE_SECONDS" envDefault:"10"`
+
+	// LeaseTTLSeconds is how long a job stays owned by an instance that stopped renewing it.
+	LeaseTTLSeconds int `env:"LEASE_TTL_SECONDS" envDefault:"30"`
+	// ArchiveCodec encodes the S3 copy of every chunk: wav (as captured), opus or flac; 16 kHz mono either way.
+	ArchiveCodec       string `env:"ARCHIVE_CODEC" envDefault:"wav"`
+	ArchiveOpusBitrate string `env:"ARCHIVE_OPUS_BITRATE" envDefault:"24k"`
 }
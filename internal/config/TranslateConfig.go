@@ -0,0 +1,9 @@
+package config
+
+// TranslateConfig controls translation of chunk transcripts. Requests go to the
+// OpenAI-compatible chat completions API configured in OpenAIConfig.
+type TranslateConfig struct {
+	Enabled        bool   `env:"ENABLED" envDefault:"false"`
+	TargetLanguage string `env:"TARGET_LANGUAGE" envDefault:"en"`
+	Model          string `env:"MODEL" envDefault:"gpt-4o-mini"`
+}
@@ -2,7 +2,34 @@ package config
 
 // WhisperConfig holds connection for Faster-Whisper HTTP server (linuxserver/faster-whisper)
 type WhisperConfig struct {
-	URL            string `env:"URL" envDefault:"http://localhost:10300"`
-	Model          string `env:"MODEL" envDefault:"base"`
-	TimeoutSeconds int    `env:"TIMEOUT_SECONDS" envDefault:"600"`
+	URL string `env:"URL" envDefault:"http://localhost:10300"`
+	// URLs lists several REST Whisper servers to balance chunks across; when empty, URL is the only endpoint.
+	URLs           []string `env:"URLS"`
+	Model          string   `env:"MODEL" envDefault:"base"`
+	TimeoutSeconds int      `env:"TIMEOUT_SECONDS" envDefault:"600"`
+	// Backends is the ordered transcription backend chain: the first one is primary and the
+	// rest are tried in order when it fails. Known: rest (URL/URLS above), openai (OPENAI_*), whispercpp.
+	Backends []string `env:"BACKENDS" envDefault:"rest"`
+	// CppURL is the whisper.cpp server used by the whispercpp backend.
+	CppURL string `env:"CPP_URL" envDefault:"http://localhost:8080"`
+	// HealthCheckIntervalSeconds is how often every REST endpoint is probed.
+	HealthCheckIntervalSeconds int `env:"HEALTH_CHECK_INTERVAL_SECONDS" envDefault:"15"`
+	// BreakerFailures consecutive failures take an endpoint out of rotation for BreakerCooldownSeconds.
+	BreakerFailures        int `env:"BREAKER_FAILURES" envDefault:"3"`
+	BreakerCooldownSeconds int `env:"BREAKER_COOLDOWN_SECONDS" envDefault:"30"`
+	// MaxConcurrent bounds Whisper requests in flight across all jobs of this instance;
+	// chunks of live sources are admitted before backfill chunks.
+	MaxConcurrent int `env:"MAX_CONCURRENT" envDefault:"4"`
+	// Retry policy of a chunk's Whisper request: up to RetryAttempts attempts with exponential
+	// backoff from RetryBackoffSeconds to RetryMaxBackoffSeconds, randomized by ±RetryJitter
+	// (fraction), and no retry once RetryMaxElapsedSeconds passed (0 = no limit). A longer
+	// Retry-After from the server wins over the computed backoff.
+	RetryAttempts          int     `env:"RETRY_ATTEMPTS" envDefault:"3"`
+	RetryBackoffSeconds    int     `env:"RETRY_BACKOFF_SECONDS" envDefault:"2"`
+	RetryMaxBackoffSeconds int     `env:"RETRY_MAX_BACKOFF_SECONDS" envDefault:"60"`
+	RetryJitter            float64 `env:"RETRY_JITTER" envDefault:"0.2"`
+	RetryMaxElapsedSeconds int     `env:"RETRY_MAX_ELAPSED_SECONDS" envDefault:"900"`
+	// UploadCompressed sends Whisper the Transcribe.ArchiveCodec file instead of the WAV
+	// (except overlap audio). whisper.cpp only decodes it when its server runs with --convert.
+	UploadCompressed bool `env:"UPLOAD_COMPRESSED" envDefault:"false"`
 }
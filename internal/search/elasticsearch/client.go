@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -43,6 +44,34 @@ func (c *Client) Ping(ctx context.Context) error {
 	return fmt.Errorf("elasticsearch ping failed: status=%d", resp.StatusCode)
 }
 
+// EnsureIndex creates the index with the given settings/mappings body.
+// An index that already exists is left untouched and is not an error.
+func (c *Client) EnsureIndex(ctx context.Context, index string, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/%s", c.BaseURL, index)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode == http.StatusBadRequest && bytes.Contains(msg, []byte("resource_already_exists_exception")) {
+		return nil
+	}
+	return fmt.Errorf("elasticsearch create index failed: status=%d: %s", resp.StatusCode, msg)
+}
+
 // IndexText indexes a simple JSON document into the provided index with the provided ID.
 func (c *Client) IndexText(ctx context.Context, index, docID string, body any) error {
 	b, err := json.Marshal(body)
@@ -0,0 +1,44 @@
+package transribe
+
+import (
+	"errors"
+
+	"news-scrabber/internal/transcribe"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// CancelTranscribeAction requests cancellation of a queued or running transcription job.
+//
+// DELETE /api/v1/transcribe-requests/:job_id
+// Returns: 202 {"job_id": "...", "state": "cancelling"}, 404 when unknown, 409 when already finished.
+type CancelTranscribeAction struct {
+	pub    transcribe.TranscribeEventPublisher
+	status *transcribe.StatusRegistry
+}
+
+func NewCancelTranscribeAction(pub transcribe.TranscribeEventPublisher, status *transcribe.StatusRegistry) *CancelTranscribeAction {
+	return &CancelTranscribeAction{pub: pub, status: status}
+}
+
+// Handle publishes the cancellation event; the owning Dispatcher stops the job.
+func (a *CancelTranscribeAction) Handle(c fiber.Ctx) error {
+	jobID := c.Params("job_id")
+	if jobID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "job_id is required"})
+	}
+	st, err := a.status.Get(jobID)
+	if errors.Is(err, transcribe.ErrJobNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "job not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if st.IsTerminal() {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "job already ended", "state": st.State})
+	}
+	if err := a.pub.PublishVideoTranscribeCancelRequested(c.Context(), jobID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"job_id": jobID, "state": "cancelling"})
+}
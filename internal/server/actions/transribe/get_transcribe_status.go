@@ -0,0 +1,37 @@
+package transribe
+
+import (
+	"errors"
+
+	"news-scrabber/internal/transcribe"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// GetTranscribeStatusAction returns the lifecycle record of a transcription job.
+//
+// GET /api/v1/transcribe-requests/:job_id
+// Returns: 200 JobStatus JSON, 404 when the job is unknown.
+type GetTranscribeStatusAction struct {
+	status *transcribe.StatusRegistry
+}
+
+func NewGetTranscribeStatusAction(status *transcribe.StatusRegistry) *GetTranscribeStatusAction {
+	return &GetTranscribeStatusAction{status: status}
+}
+
+// Handle looks the job up in the status registry.
+func (a *GetTranscribeStatusAction) Handle(c fiber.Ctx) error {
+	jobID := c.Params("job_id")
+	if jobID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "job_id is required"})
+	}
+	st, err := a.status.Get(jobID)
+	if errors.Is(err, transcribe.ErrJobNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "job not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(st)
+}
@@ -0,0 +1,37 @@
+package transribe
+
+import (
+	"strconv"
+
+	"news-scrabber/internal/transcribe"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ListDeadLettersAction lists transcribe requests that exhausted their deliveries.
+//
+// GET /api/v1/transcribe-dead-letters?limit=100
+// Returns: 200 {"items": [{"seq": 42, "job_id": "...", "reason": "...", ...}]}, oldest first.
+type ListDeadLettersAction struct {
+	dead *transcribe.DeadLetterStore
+}
+
+func NewListDeadLettersAction(dead *transcribe.DeadLetterStore) *ListDeadLettersAction {
+	return &ListDeadLettersAction{dead: dead}
+}
+
+func (a *ListDeadLettersAction) Handle(c fiber.Ctx) error {
+	limit := 100
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit must be between 1 and 1000"})
+		}
+		limit = n
+	}
+	items, err := a.dead.List(c.Context(), limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"items": items})
+}
@@ -0,0 +1,37 @@
+package transribe
+
+import (
+	"errors"
+	"strconv"
+
+	"news-scrabber/internal/transcribe"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ReplayDeadLetterAction requeues a dead-lettered transcribe request under its job ID.
+//
+// POST /api/v1/transcribe-dead-letters/:seq/replay
+// Returns: 202 {"job_id": "...", "state": "queued"}, 404 when no such dead letter.
+type ReplayDeadLetterAction struct {
+	dead *transcribe.DeadLetterStore
+}
+
+func NewReplayDeadLetterAction(dead *transcribe.DeadLetterStore) *ReplayDeadLetterAction {
+	return &ReplayDeadLetterAction{dead: dead}
+}
+
+func (a *ReplayDeadLetterAction) Handle(c fiber.Ctx) error {
+	seq, err := strconv.ParseUint(c.Params("seq"), 10, 64)
+	if err != nil || seq == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "seq must be a positive integer"})
+	}
+	jobID, err := a.dead.Replay(c.Context(), seq)
+	if errors.Is(err, transcribe.ErrDeadLetterNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "dead letter not found"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"job_id": jobID, "state": transcribe.JobStateQueued})
+}
@@ -1,6 +1,8 @@
 package transribe
 
 import (
+	"errors"
+
 	"news-scrabber/internal/transcribe"
 
 	"github.com/gofiber/fiber/v3"
@@ -10,8 +12,13 @@ import (
 // Pattern: Actions — keep endpoint logic in small, testable units.
 //
 // POST /api/v1/transcribe-requests
-// Body: {"url": "...", "job_id": "optional"}
-// Returns: 202 {"job_id": "..."}
+// Body: {"url": "...", "job_id": "optional", "segment_seconds": 60, "window_size": 7,
+//        "model": "small", "language": "uk", "beam_size": 1, "audio_stream": "0:a:0"}
+// All fields except url are optional; unset options use the configured defaults.
+// An optional Idempotency-Key header makes client retries return the first job id
+// instead of queueing the source again; reusing a key for another url is a 422.
+// Returns: 202 {"job_id": "..."}, or 200 {"job_id": "...", "existing": true} when a job
+// is already transcribing the same source (e.g. a live channel submitted twice).
 //
 // It leverages TranscribeEventPublisher to emit an event to NATS.
 
@@ -19,14 +26,19 @@ import (
 type RequestTranscribeRequest struct {
 	URL   string `json:"url"`
 	JobID string `json:"job_id"`
+	transcribe.JobOptions
 }
 
+// maxIdempotencyKeyLen bounds the Idempotency-Key header.
+const maxIdempotencyKeyLen = 255
+
 type RequestTranscribeAction struct {
-	pub transcribe.TranscribeEventPublisher
+	pub    transcribe.TranscribeEventPublisher
+	leases *transcribe.LeaseManager
 }
 
-func NewRequestTranscribeAction(pub transcribe.TranscribeEventPublisher) *RequestTranscribeAction {
-	return &RequestTranscribeAction{pub: pub}
+func NewRequestTranscribeAction(pub transcribe.TranscribeEventPublisher, leases *transcribe.LeaseManager) *RequestTranscribeAction {
+	return &RequestTranscribeAction{pub: pub, leases: leases}
 }
 
 // Handle processes the HTTP request and publishes the corresponding event.
@@ -38,7 +50,21 @@ func (a *RequestTranscribeAction) Handle(c fiber.Ctx) error {
 	if req.URL == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "url is required"})
 	}
-	jobID, err := a.pub.PublishVideoTranscribeRequested(c.Context(), req.URL, req.JobID)
+	if err := req.JobOptions.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	idemKey := c.Get("Idempotency-Key")
+	if len(idemKey) > maxIdempotencyKeyLen {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Idempotency-Key is too long"})
+	}
+	// best-effort: if the lease lookup fails the Dispatcher still drops duplicates
+	if holder, err := a.leases.SourceHolder(c.Context(), req.URL); err == nil && holder != nil && holder.JobID != req.JobID {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"job_id": holder.JobID, "existing": true})
+	}
+	jobID, err := a.pub.PublishVideoTranscribeRequested(c.Context(), req.URL, req.JobID, idemKey, req.JobOptions)
+	if errors.Is(err, transcribe.ErrIdempotencyKeyReused) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -4,11 +4,23 @@ import (
 	"news-scrabber/internal/server/actions/transribe"
 
 	"github.com/gofiber/fiber/v3"
+	"go.uber.org/fx"
 )
 
+// Actions bundles all HTTP actions so new endpoints don't change constructor signatures.
+type Actions struct {
+	fx.In
+
+	RequestTranscribe   *transribe.RequestTranscribeAction
+	GetTranscribeStatus *transribe.GetTranscribeStatusAction
+	CancelTranscribe    *transribe.CancelTranscribeAction
+	ListDeadLetters     *transribe.ListDeadLettersAction
+	ReplayDeadLetter    *transribe.ReplayDeadLetterAction
+}
+
 // RegisterRoutes wires all HTTP routes for the application.
 // Split into a separate file from server.go to keep routing concerns isolated.
-func RegisterRoutes(app *fiber.App, act *transribe.RequestTranscribeAction) {
+func RegisterRoutes(app *fiber.App, act Actions) {
 	// Health and readiness endpoints
 	app.Get("/healthz", func(c fiber.Ctx) error {
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -22,5 +34,9 @@ func RegisterRoutes(app *fiber.App, act *transribe.RequestTranscribeAction) {
 
 	// Transcription API
 	v1 := app.Group("/api/v1")
-	v1.Post("/transcribe-requests", act.Handle)
+	v1.Post("/transcribe-requests", act.RequestTranscribe.Handle)
+	v1.Get("/transcribe-requests/:job_id", act.GetTranscribeStatus.Handle)
+	v1.Delete("/transcribe-requests/:job_id", act.CancelTranscribe.Handle)
+	v1.Get("/transcribe-dead-letters", act.ListDeadLetters.Handle)
+	v1.Post("/transcribe-dead-letters/:seq/replay", act.ReplayDeadLetter.Handle)
 }
@@ -9,11 +9,10 @@ import (
 	"go.uber.org/zap"
 
 	"news-scrabber/internal/config"
-	"news-scrabber/internal/server/actions/transribe"
 )
 
 // NewFiberApp constructs a Fiber application and registers routes.
-func NewFiberApp(cfg *config.Config, log *zap.Logger, act *transribe.RequestTranscribeAction) *fiber.App {
+func NewFiberApp(cfg *config.Config, log *zap.Logger, act Actions) *fiber.App {
 	app := fiber.New()
 
 	// Register all routes
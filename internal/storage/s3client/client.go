@@ -1,17 +1,38 @@
 package s3client
 
 import (
+	"bytes"
 	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"news-scrabber/internal/config"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
 
-// Client is a minimal placeholder S3 client suitable for Seaweed S3 gateway.
+const (
+	// multipartThreshold is the file size above which Upload switches to multipart upload.
+	multipartThreshold = 16 << 20
+	// partSize is the size of each multipart part (S3 minimum is 5 MiB except for the last part).
+	partSize = 8 << 20
+)
+
+// ErrNotFound is returned by Get and Head when the object does not exist.
+var ErrNotFound = errors.New("s3: object not found")
+
+// Client is a minimal S3 client suitable for Seaweed S3 gateway.
+// It speaks path-style requests signed with AWS Signature Version 4.
 type Client struct {
 	HTTP      *http.Client
 	Endpoint  string
@@ -23,9 +44,36 @@ type Client struct {
 	log       *zap.Logger
 }
 
+// ObjectInfo describes a stored object as returned by Head and List.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	ContentType  string
+	LastModified time.Time
+}
+
+// Error is an S3 error response.
+type Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3 http %d", e.StatusCode)
+	}
+	return fmt.Sprintf("s3 http %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
 func New(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger) (*Client, error) {
+	to := 120 * time.Second
+	if cfg.S3.TimeoutSeconds > 0 {
+		to = time.Duration(cfg.S3.TimeoutSeconds) * time.Second
+	}
 	c := &Client{
-		HTTP:      &http.Client{},
+		HTTP:      &http.Client{Timeout: to},
 		Endpoint:  cfg.S3.Endpoint,
 		Region:    cfg.S3.Region,
 		Bucket:    cfg.S3.Bucket,
@@ -37,7 +85,11 @@ func New(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger) (*Client, error)
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			log.Info("S3 client ready (placeholder)",
+			// Best-effort: SeaweedFS does not auto-create buckets on first PutObject.
+			if err := c.EnsureBucket(ctx); err != nil {
+				c.log.Warn("ensure bucket failed", zap.Error(err), zap.String("bucket", c.Bucket))
+			}
+			log.Info("S3 client ready",
 				zap.String("endpoint", c.Endpoint),
 				zap.String("bucket", c.Bucket),
 			)
@@ -49,16 +101,345 @@ func New(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger) (*Client, error)
 	return c, nil
 }
 
-// Upload uploads a local file under the provided key. Placeholder returns the key without real upload.
-// Replace with a proper S3 SDK or SeaweedFS S3 signed request.
+// EnsureBucket creates the configured bucket if it does not exist yet.
+func (c *Client) EnsureBucket(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodHead, "", nil, nil, nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		return &Error{StatusCode: resp.StatusCode}
+	}
+	resp, err = c.do(ctx, http.MethodPut, "", nil, nil, nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+// Upload uploads a local file under the provided key and returns the key.
+// Files larger than multipartThreshold are sent with multipart upload.
 func (c *Client) Upload(ctx context.Context, key, localPath string) (string, error) {
-	if _, err := os.Stat(localPath); err != nil {
+	info, err := os.Stat(localPath)
+	if err != nil {
 		return "", err
 	}
 	if key == "" {
 		key = filepath.Base(localPath)
 	}
-	c.log.Info("S3 upload (placeholder)", zap.String("key", key), zap.String("path", localPath))
-	// TODO: implement actual upload using AWS SDK or MinIO SDK.
+	key = strings.TrimLeft(filepath.ToSlash(key), "/")
+	contentType := mime.TypeByExtension(filepath.Ext(localPath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	if info.Size() > multipartThreshold {
+		err = c.uploadMultipart(ctx, key, localPath, contentType)
+	} else {
+		err = c.uploadSingle(ctx, key, localPath, contentType)
+	}
+	if err != nil {
+		return "", err
+	}
+	c.log.Debug("S3 upload done", zap.String("key", key), zap.Int64("size", info.Size()))
 	return key, nil
 }
+
+func (c *Client) uploadSingle(ctx context.Context, key, localPath, contentType string) error {
+	body, err := os.ReadFile(localPath)
+	if err != nil {
+		return err
+	}
+	return c.Put(ctx, key, body, contentType)
+}
+
+// Put stores an in-memory payload under key.
+func (c *Client) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	hdr := http.Header{}
+	if contentType != "" {
+		hdr.Set("Content-Type", contentType)
+	}
+	resp, err := c.do(ctx, http.MethodPut, key, nil, hdr, body, hashHex(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+type initiateMultipartResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completePart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name       `xml:"CompleteMultipartUpload"`
+	Parts   []completePart `xml:"Part"`
+}
+
+func (c *Client) uploadMultipart(ctx context.Context, key, localPath, contentType string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hdr := http.Header{}
+	hdr.Set("Content-Type", contentType)
+	resp, err := c.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, hdr, nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	var initRes initiateMultipartResult
+	err = decodeXML(resp, &initRes)
+	if err != nil {
+		return fmt.Errorf("initiate multipart: %w", err)
+	}
+	uploadID := initRes.UploadID
+
+	abort := func() {
+		// use a fresh context: the caller's one may already be cancelled
+		actx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		resp, err := c.do(actx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, nil, emptyPayloadHash)
+		if err != nil {
+			c.log.Warn("abort multipart upload failed", zap.String("key", key), zap.Error(err))
+			return
+		}
+		resp.Body.Close()
+	}
+
+	var parts []completePart
+	buf := make([]byte, partSize)
+	for partNumber := 1; ; partNumber++ {
+		n, rerr := io.ReadFull(f, buf)
+		if n > 0 {
+			chunk := buf[:n]
+			q := url.Values{
+				"partNumber": {strconv.Itoa(partNumber)},
+				"uploadId":   {uploadID},
+			}
+			resp, err := c.do(ctx, http.MethodPut, key, q, nil, chunk, hashHex(chunk))
+			if err != nil {
+				abort()
+				return fmt.Errorf("upload part %d: %w", partNumber, err)
+			}
+			err = checkResponse(resp)
+			resp.Body.Close()
+			if err != nil {
+				abort()
+				return fmt.Errorf("upload part %d: %w", partNumber, err)
+			}
+			parts = append(parts, completePart{PartNumber: partNumber, ETag: resp.Header.Get("ETag")})
+		}
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rerr != nil {
+			abort()
+			return rerr
+		}
+	}
+
+	body, err := xml.Marshal(completeMultipartUpload{Parts: parts})
+	if err != nil {
+		abort()
+		return err
+	}
+	resp, err = c.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, nil, body, hashHex(body))
+	if err != nil {
+		abort()
+		return fmt.Errorf("complete multipart: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		abort()
+		return fmt.Errorf("complete multipart: %w", err)
+	}
+	// S3 may report a failed completion with 200 and an <Error> body.
+	b, _ := io.ReadAll(resp.Body)
+	if bytes.Contains(b, []byte("<Error>")) {
+		s3err := &Error{StatusCode: resp.StatusCode}
+		_ = xml.Unmarshal(b, s3err)
+		return fmt.Errorf("complete multipart: %w", s3err)
+	}
+	return nil
+}
+
+// Get returns the object body. The caller must close it.
+func (c *Client) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil, nil, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Head returns object metadata without fetching the body.
+func (c *Client) Head(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := c.do(ctx, http.MethodHead, key, nil, nil, nil, emptyPayloadHash)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return ObjectInfo{}, err
+	}
+	info := ObjectInfo{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ETag:        strings.Trim(resp.Header.Get("ETag"), `"`),
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModified = lm
+	}
+	return info, nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		ETag         string    `xml:"ETag"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns all objects whose key starts with prefix, following pagination.
+func (c *Client) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var out []ObjectInfo
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			q.Set("prefix", prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		resp, err := c.do(ctx, http.MethodGet, "", q, nil, nil, emptyPayloadHash)
+		if err != nil {
+			return nil, err
+		}
+		var res listBucketResult
+		if err := decodeXML(resp, &res); err != nil {
+			return nil, err
+		}
+		for _, o := range res.Contents {
+			out = append(out, ObjectInfo{
+				Key:          o.Key,
+				Size:         o.Size,
+				ETag:         strings.Trim(o.ETag, `"`),
+				LastModified: o.LastModified,
+			})
+		}
+		if !res.IsTruncated || res.NextContinuationToken == "" {
+			return out, nil
+		}
+		token = res.NextContinuationToken
+	}
+}
+
+// Delete removes the object. Deleting a missing key is not an error.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil, nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// baseURL returns the endpoint with a scheme; UseSSL picks it when the endpoint has none.
+func (c *Client) baseURL() string {
+	ep := strings.TrimRight(c.Endpoint, "/")
+	if strings.HasPrefix(ep, "http://") || strings.HasPrefix(ep, "https://") {
+		return ep
+	}
+	if c.UseSSL {
+		return "https://" + ep
+	}
+	return "http://" + ep
+}
+
+// buildURL returns a path-style object URL (<endpoint>/<bucket>/<key>) with SigV4-compatible escaping.
+func (c *Client) buildURL(key string, query url.Values) (*url.URL, error) {
+	u, err := url.Parse(c.baseURL())
+	if err != nil {
+		return nil, err
+	}
+	p := "/" + c.Bucket
+	if key != "" {
+		p += "/" + key
+	}
+	u.Path = p
+	u.RawPath = awsEscape(p, false)
+	u.RawQuery = canonicalQuery(query)
+	return u, nil
+}
+
+func (c *Client) do(ctx context.Context, method, key string, query url.Values, hdr http.Header, body []byte, payloadHash string) (*http.Response, error) {
+	u, err := c.buildURL(key, query)
+	if err != nil {
+		return nil, err
+	}
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), rd)
+	if err != nil {
+		return nil, err
+	}
+	// NewRequest re-parses the URL; keep our escaping so the signature matches the wire path.
+	req.URL = u
+	for k, v := range hdr {
+		req.Header[k] = v
+	}
+	if c.AccessKey != "" {
+		signV4(req, payloadHash, c.AccessKey, c.SecretKey, c.Region, time.Now())
+	}
+	return c.HTTP.Do(req)
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	s3err := &Error{StatusCode: resp.StatusCode}
+	if resp.Body != nil {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		_ = xml.Unmarshal(b, s3err)
+	}
+	return s3err
+}
+
+func decodeXML(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,96 @@
+package s3client
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeMultipart is an S3 server that accepts multipart uploads and records what it saw.
+type fakeMultipart struct {
+	failPart string // partNumber answered with 500
+
+	mu        sync.Mutex
+	parts     map[string]int // partNumber -> size
+	completed []completePart
+	aborted   bool
+}
+
+func (f *fakeMultipart) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		_, _ = w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>up-1</UploadId></InitiateMultipartUploadResult>`))
+	case r.Method == http.MethodPut && q.Get("uploadId") == "up-1":
+		n := q.Get("partNumber")
+		if n == f.failPart {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		f.parts[n] = len(b)
+		w.Header().Set("ETag", `"etag-`+n+`"`)
+	case r.Method == http.MethodPost && q.Get("uploadId") == "up-1":
+		var req completeMultipartUpload
+		if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.completed = req.Parts
+		_, _ = w.Write([]byte(`<CompleteMultipartUploadResult><Key>k</Key></CompleteMultipartUploadResult>`))
+	case r.Method == http.MethodDelete && q.Get("uploadId") == "up-1":
+		f.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func multipartFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "big.wav")
+	require.NoError(t, os.WriteFile(path, make([]byte, 2*partSize+1<<20), 0o644)) // above multipartThreshold
+	return path
+}
+
+func TestUploadMultipart(t *testing.T) {
+	fake := &fakeMultipart{parts: map[string]int{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	c := &Client{HTTP: srv.Client(), Endpoint: srv.URL, Bucket: "news", log: zap.NewNop()}
+
+	key, err := c.Upload(t.Context(), "raw/job-1/big.wav", multipartFile(t))
+	require.NoError(t, err)
+	assert.Equal(t, "raw/job-1/big.wav", key)
+
+	assert.Equal(t, map[string]int{"1": partSize, "2": partSize, "3": 1 << 20}, fake.parts)
+	assert.Equal(t, []completePart{
+		{PartNumber: 1, ETag: `"etag-1"`},
+		{PartNumber: 2, ETag: `"etag-2"`},
+		{PartNumber: 3, ETag: `"etag-3"`},
+	}, fake.completed)
+	assert.False(t, fake.aborted)
+}
+
+func TestUploadMultipartAbortsOnPartFailure(t *testing.T) {
+	fake := &fakeMultipart{parts: map[string]int{}, failPart: "2"}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	c := &Client{HTTP: srv.Client(), Endpoint: srv.URL, Bucket: "news", log: zap.NewNop()}
+
+	_, err := c.Upload(t.Context(), "raw/job-1/big.wav", multipartFile(t))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "upload part 2")
+	assert.True(t, fake.aborted, "the upload is aborted so the parts don't linger")
+	assert.Nil(t, fake.completed)
+}
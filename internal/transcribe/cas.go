@@ -0,0 +1,42 @@
+package transcribe
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// casStore is the compare-and-set subset of a KV bucket used for records that several
+// instances write: Create of an existing key and Update/Delete at a stale revision fail
+// with jetstream.ErrKeyExists; a missing key is jetstream.ErrKeyNotFound.
+type casStore interface {
+	Create(ctx context.Context, key string, value []byte) (uint64, error)
+	Get(ctx context.Context, key string) (value []byte, revision uint64, err error)
+	Update(ctx context.Context, key string, value []byte, revision uint64) (uint64, error)
+	Delete(ctx context.Context, key string, revision uint64) error
+}
+
+// jsCASStore is the NATS KV casStore.
+type jsCASStore struct {
+	kv jetstream.KeyValue
+}
+
+func (s jsCASStore) Create(ctx context.Context, key string, value []byte) (uint64, error) {
+	return s.kv.Create(ctx, key, value)
+}
+
+func (s jsCASStore) Get(ctx context.Context, key string) ([]byte, uint64, error) {
+	entry, err := s.kv.Get(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entry.Value(), entry.Revision(), nil
+}
+
+func (s jsCASStore) Update(ctx context.Context, key string, value []byte, revision uint64) (uint64, error) {
+	return s.kv.Update(ctx, key, value, revision)
+}
+
+func (s jsCASStore) Delete(ctx context.Context, key string, revision uint64) error {
+	return s.kv.Delete(ctx, key, jetstream.LastRevision(revision))
+}
@@ -0,0 +1,68 @@
+package transcribe
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"news-scrabber/internal/kv"
+
+	"go.uber.org/zap"
+)
+
+// JobCheckpoint is the durable progress of an ingest job. A job resumed on any
+// instance restores it to continue chunk numbering and the rolling window.
+type JobCheckpoint struct {
+	JobID          string   `json:"job_id"`
+	LastChunkIndex int      `json:"last_chunk_index"` // last chunk whose event was emitted
+	Window         []string `json:"window"`           // rolling window texts, oldest first
+	// StreamOffsetSeconds is how much source audio the emitted chunks cover.
+	// Seekable sources restart ffmpeg from this position.
+	StreamOffsetSeconds float64   `json:"stream_offset_seconds"`
+	Chunks              int       `json:"chunks"`   // chunks emitted so far
+	Failures            int       `json:"failures"` // chunks skipped after a processing error
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// CheckpointStore persists JobCheckpoint records in the shared KV store.
+type CheckpointStore struct {
+	store kv.KVStore
+	log   *zap.Logger
+}
+
+func NewCheckpointStore(store kv.KVStore, log *zap.Logger) *CheckpointStore {
+	return &CheckpointStore{
+		store: store,
+		log:   log.With(zap.String("component", "transcribe.checkpoint")),
+	}
+}
+
+// Load returns the job checkpoint or nil when the job has none yet.
+func (s *CheckpointStore) Load(jobID string) (*JobCheckpoint, error) {
+	b, err := s.store.Get(checkpointKey(jobID))
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, nil
+	}
+	var cp JobCheckpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, fmt.Errorf("decode checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// Save stores the checkpoint. It shares the retention of job status records.
+func (s *CheckpointStore) Save(cp JobCheckpoint) error {
+	cp.UpdatedAt = time.Now().UTC()
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return s.store.Set(checkpointKey(cp.JobID), b, jobStatusTTL)
+}
+
+func checkpointKey(jobID string) string {
+	return "transcribe.checkpoint." + kvSafe(jobID)
+}
@@ -0,0 +1,86 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"news-scrabber/internal/config"
+
+	"go.uber.org/zap"
+)
+
+// audioCodec is a compressed encoding for the archived copy of a chunk. Chunks stay 16 kHz
+// mono pcm_s16le WAV on disk (silence cutting, overlap and durations rely on it); the
+// encoded file is written next to the WAV and shares its name with another extension.
+type audioCodec struct {
+	name   string
+	ext    string
+	format string   // ffmpeg muxer
+	args   []string // ffmpeg encoder args
+}
+
+// archiveExts lists the extensions of every archive codec, so leftovers of a previous
+// run are found even if the codec changed in between.
+var archiveExts = []string{".ogg", ".flac"}
+
+// archiveCodec returns the configured Transcribe.ArchiveCodec; nil keeps the WAV as is.
+func archiveCodec(tc config.TranscribeConfig) (*audioCodec, error) {
+	switch strings.ToLower(strings.TrimSpace(tc.ArchiveCodec)) {
+	case "", "wav":
+		return nil, nil
+	case "opus":
+		bitrate := tc.ArchiveOpusBitrate
+		if bitrate == "" {
+			bitrate = "24k"
+		}
+		// Ogg rather than a bare .opus extension: every Whisper backend and S3 viewer knows it.
+		return &audioCodec{name: "opus", ext: ".ogg", format: "ogg",
+			args: []string{"-c:a", "libopus", "-b:a", bitrate, "-application", "voip"}}, nil
+	case "flac":
+		return &audioCodec{name: "flac", ext: ".flac", format: "flac",
+			args: []string{"-c:a", "flac", "-compression_level", "8"}}, nil
+	default:
+		return nil, fmt.Errorf("unknown archive codec %q, expected wav, opus or flac", tc.ArchiveCodec)
+	}
+}
+
+// archiveAudio encodes the chunk WAV with the archive codec and returns the encoded file.
+// It returns wavPath itself when no codec is configured or encoding failed (best-effort:
+// archiving the WAV beats losing the chunk). A complete encoding from a previous run is reused.
+func (j *IngestJob) archiveAudio(ctx context.Context, wavPath string) string {
+	if j.codec == nil {
+		return wavPath
+	}
+	out := strings.TrimSuffix(wavPath, ".wav") + j.codec.ext
+	if info, err := os.Stat(out); err == nil && info.Size() > 0 {
+		return out
+	}
+	// written under a temporary name and renamed, so a file at out is always complete
+	tmp := out + ".part"
+	args := []string{"-nostdin", "-hide_banner", "-loglevel", "error", "-y",
+		"-i", wavPath,
+		"-ac", "1",
+		"-ar", "16000",
+	}
+	args = append(args, j.codec.args...)
+	args = append(args, "-f", j.codec.format, tmp)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, j.cfg.Transcribe.FFmpegPath, args...)
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err == nil {
+		err = os.Rename(tmp, out)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		j.log.Warn("encode archive audio failed, keeping wav", zap.Error(err), zap.String("codec", j.codec.name),
+			zap.String("file", wavPath), zap.String("stderr", strings.TrimSpace(stderr.String())))
+		return wavPath
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+package transcribe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"news-scrabber/internal/config"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// SubjectDeadLetter is the NATS subject for transcribe requests that exhausted their retries.
+const SubjectDeadLetter = "news.transcribe.dead"
+
+// ErrDeadLetterNotFound is returned when no dead-lettered request has the given sequence.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetterEvent wraps a transcribe request that was given up on.
+type DeadLetterEvent struct {
+	Event      string          `json:"event"`
+	JobID      string          `json:"job_id,omitempty"`
+	URL        string          `json:"url,omitempty"`
+	Reason     string          `json:"reason"`
+	Deliveries uint64          `json:"deliveries"` // failed runs; deliveries for unreadable payloads
+	Payload    json.RawMessage `json:"payload"`    // the original request message
+	DeadAt     time.Time       `json:"dead_at"`
+}
+
+// DeadLetter is a dead-lettered request as stored in the events stream.
+type DeadLetter struct {
+	Seq uint64 `json:"seq"` // stream sequence, used to replay it
+	DeadLetterEvent
+}
+
+// DeadLetterStore dead-letters failed requests and lists or replays them. The dead
+// letters live in the events stream under SubjectDeadLetter; a replayed one is removed.
+type DeadLetterStore struct {
+	js     jetstream.JetStream
+	stream string
+	pub    TranscribeEventPublisher
+	log    *zap.Logger
+}
+
+func NewDeadLetterStore(js jetstream.JetStream, cfg *config.Config, pub TranscribeEventPublisher, log *zap.Logger) *DeadLetterStore {
+	return &DeadLetterStore{
+		js:     js,
+		stream: eventsStream(cfg),
+		pub:    pub,
+		log:    log.With(zap.String("component", "transcribe.deadletter")),
+	}
+}
+
+// Publish republishes the request payload to SubjectDeadLetter with the failure reason.
+func (s *DeadLetterStore) Publish(ctx context.Context, payload []byte, reason string, deliveries uint64) error {
+	ev := DeadLetterEvent{
+		Event:      "TranscribeRequestDeadLettered",
+		Reason:     reason,
+		Deliveries: deliveries,
+		DeadAt:     time.Now().UTC(),
+	}
+	var req VideoTranscribeRequested
+	if json.Valid(payload) {
+		ev.Payload = payload
+		if err := json.Unmarshal(payload, &req); err == nil {
+			ev.JobID, ev.URL = req.JobID, req.URL
+		}
+	} else {
+		// keep unparseable payloads readable in the listing
+		ev.Payload, _ = json.Marshal(string(payload))
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if _, err := s.js.Publish(ctx, SubjectDeadLetter, b); err != nil {
+		return err
+	}
+	s.log.Warn("transcribe request dead-lettered",
+		zap.String("job", ev.JobID),
+		zap.String("reason", reason),
+		zap.Uint64("deliveries", deliveries),
+	)
+	return nil
+}
+
+// List returns up to limit dead letters, oldest first.
+func (s *DeadLetterStore) List(ctx context.Context, limit int) ([]DeadLetter, error) {
+	cons, err := s.js.OrderedConsumer(ctx, s.stream, jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{SubjectDeadLetter},
+		DeliverPolicy:  jetstream.DeliverAllPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	info, err := cons.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	want := int(info.NumPending)
+	if limit > 0 && want > limit {
+		want = limit
+	}
+
+	out := make([]DeadLetter, 0, want)
+	for len(out) < want {
+		batch, err := cons.Fetch(want-len(out), jetstream.FetchMaxWait(2*time.Second))
+		if err != nil {
+			return nil, err
+		}
+		got := 0
+		for msg := range batch.Messages() {
+			got++
+			md, err := msg.Metadata()
+			if err != nil {
+				continue
+			}
+			dl := DeadLetter{Seq: md.Sequence.Stream}
+			if err := json.Unmarshal(msg.Data(), &dl.DeadLetterEvent); err != nil {
+				s.log.Debug("skip undecodable dead letter", zap.Uint64("seq", dl.Seq), zap.Error(err))
+				continue
+			}
+			out = append(out, dl)
+		}
+		if err := batch.Error(); err != nil && !errors.Is(err, jetstream.ErrNoMessages) {
+			return nil, err
+		}
+		if got == 0 {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Replay requeues the original request of dead letter seq under its job ID and removes
+// the dead letter. It returns the job ID.
+func (s *DeadLetterStore) Replay(ctx context.Context, seq uint64) (string, error) {
+	stream, err := s.js.Stream(ctx, s.stream)
+	if err != nil {
+		return "", err
+	}
+	raw, err := stream.GetMsg(ctx, seq)
+	if errors.Is(err, jetstream.ErrMsgNotFound) {
+		return "", ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if raw.Subject != SubjectDeadLetter {
+		return "", ErrDeadLetterNotFound
+	}
+	var dl DeadLetterEvent
+	if err := json.Unmarshal(raw.Data, &dl); err != nil {
+		return "", fmt.Errorf("decode dead letter: %w", err)
+	}
+	var req VideoTranscribeRequested
+	if err := json.Unmarshal(dl.Payload, &req); err != nil || req.URL == "" {
+		return "", fmt.Errorf("dead letter %d has no replayable request", seq)
+	}
+
+	jobID, err := s.pub.PublishVideoTranscribeRequested(ctx, req.URL, req.JobID, "", req.JobOptions)
+	if err != nil {
+		return "", err
+	}
+	if err := stream.DeleteMsg(ctx, seq); err != nil {
+		s.log.Warn("delete replayed dead letter failed", zap.Error(err), zap.Uint64("seq", seq))
+	}
+	s.log.Info("dead letter replayed", zap.Uint64("seq", seq), zap.String("job", jobID))
+	return jobID, nil
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package transcribe
+
+import "errors"
+
+// diskFree is not implemented on this platform; the disk guard stays inactive.
+func diskFree(string) (uint64, error) {
+	return 0, errors.New("disk free check not supported on this platform")
+}
@@ -0,0 +1,175 @@
+package transcribe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"news-scrabber/internal/config"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// uploadedDir is the per-job subdirectory holding chunk files that are already in S3
+// and Elasticsearch, kept only for Transcribe.RetentionSeconds.
+const uploadedDir = "uploaded"
+
+// failedDir is the per-job subdirectory holding chunk files that did not make it to S3 or
+// Elasticsearch, kept for Transcribe.FailedRetentionSeconds.
+const failedDir = "failed"
+
+// DiskGuard watches free space in Transcribe.TempDir. Below the MinFreeDiskMB watermark
+// the Dispatcher stops taking new jobs until space is available again. Each check also
+// prunes kept chunk files that outlived their retention.
+type DiskGuard struct {
+	dir       string
+	minFree   uint64
+	interval  time.Duration
+	retention time.Duration
+	// failedRetention applies to failed/; 0 or less never prunes it.
+	failedRetention time.Duration
+	log             *zap.Logger
+
+	paused atomic.Bool
+	cancel context.CancelFunc
+}
+
+func NewDiskGuard(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger) *DiskGuard {
+	tc := cfg.Transcribe
+	interval := time.Duration(tc.DiskCheckIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	g := &DiskGuard{
+		dir:             tc.TempDir,
+		minFree:         uint64(max(tc.MinFreeDiskMB, 0)) << 20,
+		interval:        interval,
+		retention:       time.Duration(tc.RetentionSeconds) * time.Second,
+		failedRetention: time.Duration(tc.FailedRetentionSeconds) * time.Second,
+		log:             log.With(zap.String("component", "transcribe.diskguard")),
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			if err := os.MkdirAll(g.dir, 0o755); err != nil {
+				g.log.Warn("create temp dir failed", zap.Error(err), zap.String("dir", g.dir))
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			g.cancel = cancel
+			g.check()
+			go g.loop(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			if g.cancel != nil {
+				g.cancel()
+			}
+			return nil
+		},
+	})
+	return g
+}
+
+// Paused reports whether free space is below the watermark.
+func (g *DiskGuard) Paused() bool {
+	return g.paused.Load()
+}
+
+// WaitForSpace blocks while the guard is paused. It returns ctx.Err() if ctx ends first.
+func (g *DiskGuard) WaitForSpace(ctx context.Context) error {
+	for g.Paused() {
+		select {
+		case <-time.After(g.interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (g *DiskGuard) loop(ctx context.Context) {
+	t := time.NewTicker(g.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			g.pruneKept()
+			g.check()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check samples free space and flips the paused flag, logging on every transition.
+func (g *DiskGuard) check() {
+	if g.minFree == 0 {
+		return
+	}
+	free, err := diskFree(g.dir)
+	if err != nil {
+		g.log.Debug("disk free check failed", zap.Error(err), zap.String("dir", g.dir))
+		return
+	}
+	low := free < g.minFree
+	if g.paused.Swap(low) == low {
+		if low {
+			g.log.Warn("disk space still low", zap.Uint64("free_mb", free>>20))
+		}
+		return
+	}
+	if low {
+		g.log.Warn("free disk space below watermark, pausing new jobs",
+			zap.String("dir", g.dir),
+			zap.Uint64("free_mb", free>>20),
+			zap.Uint64("min_free_mb", g.minFree>>20),
+		)
+	} else {
+		g.log.Info("free disk space recovered, accepting new jobs", zap.Uint64("free_mb", free>>20))
+	}
+}
+
+// pruneKept deletes files under <TempDir>/<job>/uploaded and <job>/failed older than their
+// retention, and the dir of a finished job once none are left.
+func (g *DiskGuard) pruneKept() {
+	removed := g.prune(uploadedDir, g.retention) + g.prune(failedDir, g.failedRetention)
+	if removed > 0 {
+		g.log.Debug("pruned kept chunk files", zap.Int("files", removed))
+	}
+
+	done, _ := filepath.Glob(filepath.Join(g.dir, "*", jobDoneMarker))
+	for _, marker := range done {
+		jobDir := filepath.Dir(marker)
+		uploaded, _ := filepath.Glob(filepath.Join(jobDir, uploadedDir, "*"))
+		failed, _ := filepath.Glob(filepath.Join(jobDir, failedDir, "*"))
+		if len(uploaded)+len(failed) > 0 {
+			continue
+		}
+		if err := os.RemoveAll(jobDir); err != nil {
+			g.log.Warn("remove finished job dir failed", zap.Error(err), zap.String("dir", jobDir))
+		}
+	}
+}
+
+// prune deletes the files in every <job>/sub older than retention and returns how many.
+func (g *DiskGuard) prune(sub string, retention time.Duration) int {
+	if retention <= 0 {
+		return 0
+	}
+	files, _ := filepath.Glob(filepath.Join(g.dir, "*", sub, "*"))
+	cutoff := time.Now().Add(-retention)
+	removed := 0
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(f); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
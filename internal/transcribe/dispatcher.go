@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"news-scrabber/internal/config"
@@ -18,6 +21,10 @@ type Dispatcher struct {
 	js            jetstream.JetStream
 	log           *zap.Logger
 	svc           *Service
+	status        *StatusRegistry
+	disk          *DiskGuard
+	dead          *DeadLetterStore
+	leases        *LeaseManager
 	consumer      jetstream.Consumer
 	stream        string
 	subjects      string
@@ -25,19 +32,52 @@ type Dispatcher struct {
 	cancel        context.CancelFunc
 	sem           chan struct{}
 	maxConcurrent int
+	// Failed requests are redelivered after redelivery, doubling up to maxRedelivery,
+	// and dead-lettered after maxAttempts failed runs (-1: never). Runs are counted on the
+	// job status, not by JetStream, so redeliveries that are no failure (hand-backs on
+	// shutdown, lease contention) never use the budget up.
+	maxAttempts   int
+	redelivery    time.Duration
+	maxRedelivery time.Duration
+
+	cancelConsume jetstream.ConsumeContext
+	jobsMu        sync.Mutex
+	jobs          map[string]*runningJob
+
+	// Shutdown: intake stops first (intakeCtx), running jobs get grace to drain,
+	// then d.ctx cancels whatever is left.
+	msgs       jetstream.MessagesContext
+	intakeCtx  context.Context
+	stopIntake context.CancelFunc
+	draining   atomic.Bool
+	handlers   sync.WaitGroup
+	grace      time.Duration
 }
 
-func (d *Dispatcher) processMessages() {
-	msgs, err := d.consumer.Messages()
-	if err != nil {
-		d.log.Error("failed to get consumer messages", zap.Error(err))
-		return
-	}
+// runningJob is a job owned by this Dispatcher instance.
+type runningJob struct {
+	cancel    context.CancelCauseFunc
+	cancelled atomic.Bool
+	leaseLost atomic.Bool
+	drain     chan struct{}
+	drainOnce sync.Once
+}
+
+// stopIntake asks the job to stop after its in-flight chunks.
+func (rj *runningJob) stopIntake() {
+	rj.drainOnce.Do(func() { close(rj.drain) })
+}
+
+func (d *Dispatcher) processMessages(msgs jetstream.MessagesContext) {
 	for {
+		// Low disk space pauses intake; running jobs keep going.
+		if err := d.disk.WaitForSpace(d.intakeCtx); err != nil {
+			return
+		}
 		msg, err := msgs.Next()
 		if err != nil {
-			if d.ctx.Err() != nil {
-				return // context cancelled
+			if d.intakeCtx.Err() != nil || errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+				return // shutting down
 			}
 			d.log.Error("error receiving message", zap.Error(err))
 			continue
@@ -45,12 +85,12 @@ func (d *Dispatcher) processMessages() {
 		var ev VideoTranscribeRequested
 		if err := json.Unmarshal(msg.Data(), &ev); err != nil {
 			d.log.Warn("bad event payload", zap.Error(err))
-			_ = msg.Nak()
+			d.deadLetter(msg, fmt.Errorf("bad payload: %w", err), deliveries(msg))
 			continue
 		}
 		if ev.URL == "" {
 			d.log.Warn("missing url in event")
-			_ = msg.Nak()
+			d.deadLetter(msg, errors.New("missing url"), deliveries(msg))
 			continue
 		}
 
@@ -58,32 +98,79 @@ func (d *Dispatcher) processMessages() {
 		select {
 		case d.sem <- struct{}{}:
 			// acquired
-		case <-d.ctx.Done():
+		case <-d.intakeCtx.Done():
 			_ = msg.Nak()
 			return
 		}
 
+		d.handlers.Add(1)
 		go d.handleMessage(ev, msg)
 	}
 }
 
 func (d *Dispatcher) handleMessage(ev VideoTranscribeRequested, msg jetstream.Msg) {
+	defer d.handlers.Done()
 	defer func() { <-d.sem }()
 
+	if st, err := d.status.Get(ev.JobID); err == nil && st.CancelRequestedAt != nil {
+		d.log.Info("job cancelled before start", zap.String("url", ev.URL), zap.String("job", ev.JobID))
+		d.status.MarkCancelled(ev.JobID)
+		_ = msg.Ack()
+		return
+	}
+
+	lease, ok := d.acquireLease(ev, msg)
+	if !ok {
+		return
+	}
+	defer lease.Release(context.Background())
+
+	jobCtx, rj := d.trackJob(ev.JobID)
+	defer d.untrackJob(ev.JobID)
+
+	// Checked again once the job is tracked: a cancel flagged until now is seen here, a later
+	// one reaches handleCancel.
+	if !d.status.MarkRunning(ev.JobID, ev.URL) {
+		d.log.Info("job cancelled before start", zap.String("url", ev.URL), zap.String("job", ev.JobID))
+		_ = msg.Ack()
+		return
+	}
 	errCh := make(chan error, 1)
-	go func() { errCh <- d.svc.IngestURL(d.ctx, ev.URL, ev.JobID) }()
+	go func() { errCh <- d.svc.IngestURL(jobCtx, rj.drain, ev.URL, ev.JobID, ev.JobOptions) }()
 
-	ticker := time.NewTicker(10 * time.Second)
+	// heartbeats keep the message (AckWait 30s) and the leases (TTL) alive
+	ticker := time.NewTicker(min(10*time.Second, d.leases.RenewInterval()))
 	defer ticker.Stop()
 
 	for {
 		select {
 		case err := <-errCh:
-			if err != nil && !errors.Is(err, context.Canceled) {
-				d.log.Warn("job finished with error", zap.Error(err), zap.String("url", ev.URL), zap.String("job", ev.JobID))
+			if rj.leaseLost.Load() {
+				// the new owner runs the job and settles the request: leave status and message alone
+				d.log.Info("job stopped after losing its lease", zap.String("url", ev.URL), zap.String("job", ev.JobID))
+				return
+			}
+			if rj.cancelled.Load() {
+				d.log.Info("job cancelled", zap.String("url", ev.URL), zap.String("job", ev.JobID))
+				d.status.MarkCancelled(ev.JobID)
+				_ = msg.Ack()
+				return
+			}
+			if errors.Is(err, ErrJobDrained) || jobCtx.Err() != nil {
+				// Shutdown: the checkpoint is saved, so another instance resumes right away.
+				// The consumer redelivers without limit; only failed runs count (see maxAttempts).
+				d.log.Info("job handed back on shutdown", zap.String("url", ev.URL), zap.String("job", ev.JobID))
+				d.status.MarkRequeued(ev.JobID)
 				_ = msg.Nak()
+				return
+			}
+			if err != nil {
+				d.log.Warn("job finished with error", zap.Error(err), zap.String("url", ev.URL), zap.String("job", ev.JobID))
+				attempts := d.status.MarkRetrying(ev.JobID, err)
+				d.retryOrDeadLetter(msg, ev.JobID, err, attempts)
 			} else {
 				d.log.Info("job finished", zap.String("url", ev.URL), zap.String("job", ev.JobID))
+				d.status.MarkFinished(ev.JobID)
 				_ = msg.Ack()
 			}
 			return
@@ -91,17 +178,180 @@ func (d *Dispatcher) handleMessage(ev VideoTranscribeRequested, msg jetstream.Ms
 			if err := msg.InProgress(); err != nil {
 				d.log.Debug("in-progress heartbeat failed", zap.Error(err))
 			}
-		case <-d.ctx.Done():
-			return
+			if err := lease.Renew(jobCtx); errors.Is(err, ErrLeaseLost) {
+				// someone else owns the job now: stop without touching its progress
+				d.log.Warn("job lease lost, stopping", zap.String("job", ev.JobID))
+				rj.leaseLost.Store(true)
+				rj.cancel(nil)
+			} else if err != nil {
+				d.log.Warn("job lease renew failed", zap.Error(err), zap.String("job", ev.JobID))
+			}
 		}
 	}
 }
 
-func NewDispatcher(lc fx.Lifecycle, js jetstream.JetStream, log *zap.Logger, cfg *config.Config, svc *Service) (*Dispatcher, error) {
-	stream := cfg.JetStream.EventsStream
-	if stream == "" {
-		stream = "NEWS"
+// acquireLease takes the cluster-wide lease for the job. Without it the message is settled:
+// a duplicate submission of a source another job transcribes fails for good, while a job
+// held by another live instance is retried once that lease could have expired. Neither
+// counts as a failed run (see maxAttempts).
+func (d *Dispatcher) acquireLease(ev VideoTranscribeRequested, msg jetstream.Msg) (*Lease, bool) {
+	lease, err := d.leases.Acquire(d.ctx, ev.JobID, ev.URL)
+	if err == nil {
+		return lease, true
+	}
+	var held *LeaseHeldError
+	switch {
+	case errors.As(err, &held) && held.Kind == "source" && held.Holder.JobID != ev.JobID:
+		d.log.Info("source already being transcribed, dropping duplicate job",
+			zap.String("job", ev.JobID), zap.String("existing_job", held.Holder.JobID), zap.String("url", ev.URL))
+		d.status.MarkFailed(ev.JobID, fmt.Errorf("source already transcribed by job %s", held.Holder.JobID))
+		_ = msg.Ack()
+	case errors.As(err, &held):
+		d.log.Info("job owned by another instance", zap.String("job", ev.JobID), zap.String("owner", held.Holder.Owner))
+		_ = msg.NakWithDelay(d.leases.TTL())
+	default:
+		d.log.Warn("acquire job lease failed", zap.Error(err), zap.String("job", ev.JobID))
+		_ = msg.NakWithDelay(d.redelivery)
+	}
+	return nil, false
+}
+
+// retryOrDeadLetter schedules a redelivery of a failed request with exponential backoff,
+// or dead-letters it after its last allowed failed run; only then is the job marked failed.
+// attempts is the count recorded on the job status; without one the delivery count stands in.
+func (d *Dispatcher) retryOrDeadLetter(msg jetstream.Msg, jobID string, reason error, attempts int) {
+	n := uint64(attempts)
+	if n == 0 {
+		n = deliveries(msg)
+	}
+	if d.maxAttempts > 0 && n >= uint64(d.maxAttempts) {
+		if d.deadLetter(msg, reason, n) {
+			d.status.MarkFailed(jobID, reason)
+		}
+		return
+	}
+	delay := d.redelivery
+	for i := uint64(1); i < n && delay < d.maxRedelivery; i++ {
+		delay *= 2
+	}
+	delay = min(delay, d.maxRedelivery)
+	if err := msg.NakWithDelay(delay); err != nil {
+		d.log.Debug("nak with delay failed", zap.Error(err))
+	}
+}
+
+// deadLetter republishes the request to SubjectDeadLetter and terminates its delivery.
+// If the dead letter can't be published the message is left to redelivery instead and
+// deadLetter reports false.
+func (d *Dispatcher) deadLetter(msg jetstream.Msg, reason error, attempts uint64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.dead.Publish(ctx, msg.Data(), reason.Error(), attempts); err != nil {
+		d.log.Warn("dead-letter publish failed", zap.Error(err))
+		_ = msg.NakWithDelay(d.redelivery)
+		return false
+	}
+	if err := msg.Term(); err != nil {
+		d.log.Debug("term failed", zap.Error(err))
+	}
+	return true
+}
+
+// deliveries is how many times msg was delivered, this delivery included.
+func deliveries(msg jetstream.Msg) uint64 {
+	if md, err := msg.Metadata(); err == nil {
+		return md.NumDelivered
+	}
+	return 1
+}
+
+func (d *Dispatcher) trackJob(jobID string) (context.Context, *runningJob) {
+	ctx, cancel := context.WithCancelCause(d.ctx)
+	rj := &runningJob{cancel: cancel, drain: make(chan struct{})}
+	d.jobsMu.Lock()
+	d.jobs[jobID] = rj
+	if d.draining.Load() {
+		rj.stopIntake()
+	}
+	d.jobsMu.Unlock()
+	return ctx, rj
+}
+
+// shutdown stops taking messages, lets running jobs finish and checkpoint their in-flight
+// chunks for up to the grace period, then cancels the rest. Every unfinished job is Nak'ed
+// (see handleMessage) so another instance picks it up.
+func (d *Dispatcher) shutdown(ctx context.Context) {
+	d.draining.Store(true)
+	d.stopIntake()
+	if d.msgs != nil {
+		d.msgs.Stop()
+	}
+	d.jobsMu.Lock()
+	for _, rj := range d.jobs {
+		rj.stopIntake()
+	}
+	d.jobsMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.handlers.Wait()
+		close(done)
+	}()
+	grace := d.grace
+	if deadline, ok := ctx.Deadline(); ok {
+		// keep a moment to Nak cancelled jobs before the stop timeout
+		grace = min(grace, time.Until(deadline)-2*time.Second)
+	}
+	select {
+	case <-done:
+	case <-time.After(grace):
+		d.log.Warn("shutdown grace period over, cancelling running jobs", zap.Duration("grace", grace))
+	}
+	d.cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (d *Dispatcher) untrackJob(jobID string) {
+	d.jobsMu.Lock()
+	rj := d.jobs[jobID]
+	delete(d.jobs, jobID)
+	d.jobsMu.Unlock()
+	if rj != nil {
+		rj.cancel(nil)
 	}
+}
+
+// handleCancel stops the job if this instance owns it; other instances ignore the event.
+func (d *Dispatcher) handleCancel(msg jetstream.Msg) {
+	var ev VideoTranscribeCancelRequested
+	if err := json.Unmarshal(msg.Data(), &ev); err != nil {
+		d.log.Warn("bad cancel event payload", zap.Error(err))
+		return
+	}
+	d.jobsMu.Lock()
+	rj := d.jobs[ev.JobID]
+	d.jobsMu.Unlock()
+	if rj == nil {
+		return
+	}
+	d.log.Info("cancelling job on request", zap.String("job", ev.JobID))
+	rj.cancelled.Store(true)
+	rj.cancel(ErrJobCancelled) // kills ffmpeg via exec.CommandContext and stops the watcher
+}
+
+// eventsStream is the JetStream stream holding application events.
+func eventsStream(cfg *config.Config) string {
+	if cfg.JetStream.EventsStream != "" {
+		return cfg.JetStream.EventsStream
+	}
+	return "NEWS"
+}
+
+func NewDispatcher(lc fx.Lifecycle, js jetstream.JetStream, log *zap.Logger, cfg *config.Config, svc *Service, status *StatusRegistry, disk *DiskGuard, dead *DeadLetterStore, leases *LeaseManager) (*Dispatcher, error) {
+	stream := eventsStream(cfg)
 	subjects := cfg.JetStream.EventsSubjects
 	if subjects == "" {
 		subjects = "news.*"
@@ -111,50 +361,95 @@ func NewDispatcher(lc fx.Lifecycle, js jetstream.JetStream, log *zap.Logger, cfg
 	if maxConc <= 0 {
 		maxConc = 2
 	}
+	grace := time.Duration(cfg.Transcribe.ShutdownGraceSeconds) * time.Second
+	if grace <= 0 {
+		grace = 10 * time.Second
+	}
+	maxAttempts := cfg.Transcribe.MaxDeliver
+	if maxAttempts <= 0 {
+		maxAttempts = -1 // unlimited
+	}
+	redelivery := time.Duration(cfg.Transcribe.RedeliveryBackoffSeconds) * time.Second
+	if redelivery <= 0 {
+		redelivery = 10 * time.Second
+	}
+	maxRedelivery := time.Duration(cfg.Transcribe.RedeliveryMaxBackoffSeconds) * time.Second
+	if maxRedelivery < redelivery {
+		maxRedelivery = redelivery
+	}
 
 	d := &Dispatcher{
 		log:           log.With(zap.String("component", "transcribe.dispatcher")),
 		js:            js,
 		svc:           svc,
+		status:        status,
+		disk:          disk,
+		dead:          dead,
+		leases:        leases,
+		maxAttempts:   maxAttempts,
+		redelivery:    redelivery,
+		maxRedelivery: maxRedelivery,
+		grace:         grace,
 		stream:        stream,
 		subjects:      subjects,
 		sem:           make(chan struct{}, maxConc),
 		maxConcurrent: maxConc,
+		jobs:          make(map[string]*runningJob),
 	}
 	d.ctx, d.cancel = context.WithCancel(context.Background())
+	d.intakeCtx, d.stopIntake = context.WithCancel(d.ctx)
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			// Ensure stream exists (idempotent) to avoid "no response from stream" errors.
 			if _, err := d.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
 				Name:     d.stream,
-				Subjects: []string{d.subjects, SubjectVideoTranscribeRequested},
+				Subjects: []string{d.subjects, SubjectVideoTranscribeRequested, SubjectVideoTranscribeCancelRequested, SubjectJobCompleted, SubjectStreamInterrupted, SubjectStreamResumed, SubjectDeadLetter},
 			}); err != nil {
 				d.log.Warn("ensure events stream failed", zap.Error(err), zap.String("stream", d.stream), zap.String("subjects", d.subjects))
 				return err
 			}
 
-			consumer, err := d.js.CreateConsumer(ctx, d.stream, jetstream.ConsumerConfig{
+			consumer, err := d.js.CreateOrUpdateConsumer(ctx, d.stream, jetstream.ConsumerConfig{
 				Durable:       "transcribe-dispatcher",
 				AckPolicy:     jetstream.AckExplicitPolicy,
 				AckWait:       30 * time.Second,
 				MaxAckPending: d.maxConcurrent,
+				// Unlimited: JetStream would drop an exhausted message silently, so the
+				// Dispatcher enforces the limit itself and dead-letters (see maxAttempts).
+				MaxDeliver:    -1,
 				FilterSubject: SubjectVideoTranscribeRequested,
 			})
 			if err != nil {
 				return err
 			}
 			d.consumer = consumer
-			go d.processMessages()
+
+			// Cancellation requests fan out to every instance: an ordered (ephemeral) consumer
+			// per Dispatcher that only sees events published after startup.
+			cancels, err := d.js.OrderedConsumer(ctx, d.stream, jetstream.OrderedConsumerConfig{
+				FilterSubjects: []string{SubjectVideoTranscribeCancelRequested},
+				DeliverPolicy:  jetstream.DeliverNewPolicy,
+			})
+			if err != nil {
+				return err
+			}
+			if d.cancelConsume, err = cancels.Consume(d.handleCancel); err != nil {
+				return err
+			}
+
+			if d.msgs, err = consumer.Messages(); err != nil {
+				return err
+			}
+			go d.processMessages(d.msgs)
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			if d.cancel != nil {
-				d.cancel()
-			}
-			if d.consumer != nil {
-				_ = d.js.DeleteConsumer(ctx, d.stream, "transcribe-dispatcher")
+			if d.cancelConsume != nil {
+				d.cancelConsume.Stop()
 			}
+			// The durable consumer is kept: its ack state must survive restarts.
+			d.shutdown(ctx)
 			return nil
 		},
 	})
@@ -3,8 +3,12 @@ package transcribe
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
+	"news-scrabber/internal/kv"
+
 	"github.com/nats-io/nats.go/jetstream"
 	"go.uber.org/zap"
 )
@@ -12,6 +16,21 @@ import (
 // SubjectVideoTranscribeRequested is the NATS subject for requesting a new video transcription job.
 const SubjectVideoTranscribeRequested = "news.transcribe.request"
 
+// SubjectVideoTranscribeCancelRequested is the NATS subject for cancelling a transcription job.
+// Every Dispatcher instance receives it; only the one running the job acts on it.
+const SubjectVideoTranscribeCancelRequested = "news.transcribe.cancel"
+
+// SubjectJobCompleted is the NATS subject emitted when a finite source was fully transcribed.
+const SubjectJobCompleted = "news.transcribe.completed"
+
+// SubjectStreamInterrupted is the NATS subject emitted when ffmpeg lost a live source
+// and the job starts reconnecting.
+const SubjectStreamInterrupted = "news.transcribe.interrupted"
+
+// SubjectStreamResumed is the NATS subject emitted when a reconnected live source
+// produced its first segment again.
+const SubjectStreamResumed = "news.transcribe.resumed"
+
 // VideoTranscribeRequested is an event requesting to start transcription for a given video/stream URL.
 // Evolve by adding fields; keep existing fields backward compatible.
 type VideoTranscribeRequested struct {
@@ -19,48 +38,202 @@ type VideoTranscribeRequested struct {
 	URL         string    `json:"url"`
 	JobID       string    `json:"job_id,omitempty"`
 	RequestedAt time.Time `json:"requested_at"`
+	// Optional per-request settings; unset fields use TranscribeConfig defaults.
+	JobOptions
+}
+
+// VideoTranscribeCancelRequested is an event requesting to stop a queued or running job.
+type VideoTranscribeCancelRequested struct {
+	Event       string    `json:"event"`
+	JobID       string    `json:"job_id"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// JobCompletedEvent reports totals of a job whose source ended and whose chunks were all handled.
+// Totals are cumulative across resumes of the same job.
+type JobCompletedEvent struct {
+	Event        string    `json:"event"`
+	JobID        string    `json:"job_id"`
+	SourceURL    string    `json:"source_url"`
+	Chunks       int       `json:"chunks"`
+	Failures     int       `json:"failures"`
+	AudioSeconds float64   `json:"audio_seconds"`
+	CompletedAt  time.Time `json:"completed_at"`
+}
+
+// StreamInterruptedEvent reports that ffmpeg exited unexpectedly on a live source.
+type StreamInterruptedEvent struct {
+	Event          string    `json:"event"`
+	JobID          string    `json:"job_id"`
+	SourceURL      string    `json:"source_url"`
+	LastChunkIndex int       `json:"last_chunk_index"` // last segment produced before the drop; -1 for none
+	Error          string    `json:"error"`
+	InterruptedAt  time.Time `json:"interrupted_at"`
+}
+
+// StreamResumedEvent reports that a live source is producing segments again after an interruption.
+// Chunk numbering continues at NextChunkIndex; GapSeconds of the broadcast were not captured.
+type StreamResumedEvent struct {
+	Event          string    `json:"event"`
+	JobID          string    `json:"job_id"`
+	SourceURL      string    `json:"source_url"`
+	NextChunkIndex int       `json:"next_chunk_index"`
+	Restarts       int       `json:"restarts"`
+	GapSeconds     float64   `json:"gap_seconds"`
+	InterruptedAt  time.Time `json:"interrupted_at"`
+	ResumedAt      time.Time `json:"resumed_at"`
 }
 
 // TranscribeEventPublisher defines the interface to publish transcription-related events.
 type TranscribeEventPublisher interface {
 	// PublishVideoTranscribeRequested publishes a request event to start transcription.
-	// If jobID is empty, it will be auto-generated and returned.
-	PublishVideoTranscribeRequested(ctx context.Context, url, jobID string) (string, error)
+	// If jobID is empty, it will be auto-generated and returned. A non-empty idempotencyKey
+	// derives the job id, so retries of the same request return the first job id instead
+	// of queueing again.
+	PublishVideoTranscribeRequested(ctx context.Context, url, jobID, idempotencyKey string, opts JobOptions) (string, error)
+	// PublishVideoTranscribeCancelRequested flags the job as cancelled and notifies its owner.
+	PublishVideoTranscribeCancelRequested(ctx context.Context, jobID string) error
+}
+
+// idempotencyTTL bounds how long an Idempotency-Key keeps pointing at its job.
+const idempotencyTTL = 24 * time.Hour
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is sent again for another source URL.
+var ErrIdempotencyKeyReused = errors.New("idempotency key already used for another url")
+
+// idempotencyRecord is what an idempotency key maps to in the KV store.
+type idempotencyRecord struct {
+	JobID string `json:"job_id"`
+	URL   string `json:"url"`
 }
 
 // jsPublisher implements TranscribeEventPublisher using NATS JetStream.
 type jsPublisher struct {
-	js  jetstream.JetStream
-	log *zap.Logger
+	js     jetstream.JetStream
+	store  kv.KVStore
+	log    *zap.Logger
+	status *StatusRegistry
 }
 
 // NewPublisher returns a JetStream-backed publisher for transcription events.
-func NewPublisher(js jetstream.JetStream, log *zap.Logger) TranscribeEventPublisher {
-	return &jsPublisher{js: js, log: log.With(zap.String("component", "transcribe.publisher"))}
+func NewPublisher(js jetstream.JetStream, store kv.KVStore, log *zap.Logger, status *StatusRegistry) TranscribeEventPublisher {
+	return &jsPublisher{js: js, store: store, log: log.With(zap.String("component", "transcribe.publisher")), status: status}
 }
 
-func (p *jsPublisher) PublishVideoTranscribeRequested(ctx context.Context, url, jobID string) (string, error) {
+func (p *jsPublisher) PublishVideoTranscribeRequested(ctx context.Context, url, jobID, idempotencyKey string, opts JobOptions) (string, error) {
+	// The stream drops a second message with the same id inside its duplicate window, so
+	// retries of a request are published once. A job that already ended and is queued again
+	// (e.g. a replayed dead letter) gets a fresh message id: its previous request may still
+	// be inside the window.
+	msgID := jobID
+	if jobID != "" {
+		if st, err := p.status.Get(jobID); err == nil && st.FinishedAt != nil {
+			msgID = jobID + "-" + newULID(time.Now())
+		}
+	}
+	if idempotencyKey != "" {
+		rec, err := p.lookupIdempotencyKey(idempotencyKey)
+		if err != nil {
+			return "", err
+		}
+		if rec != nil {
+			if rec.URL != url {
+				return "", ErrIdempotencyKeyReused
+			}
+			p.log.Info("idempotent request replayed", zap.String("job", rec.JobID), zap.String("key", idempotencyKey))
+			return rec.JobID, nil
+		}
+		// Retries derive the same job id, so concurrent ones that both missed the lookup
+		// above are deduplicated by the stream.
+		if jobID == "" {
+			jobID = idempotentJobID(url, idempotencyKey)
+			msgID = jobID
+		}
+		if st, err := p.status.Get(jobID); err == nil && !st.Unpublished {
+			// published before, but its key record was not stored
+			if st.SourceURL != url {
+				return "", ErrIdempotencyKeyReused
+			}
+			return jobID, nil
+		}
+	}
 	if jobID == "" {
-		jobID = generateJobID()
+		jobID = newJobID(url)
+		msgID = jobID
 	}
 	ev := VideoTranscribeRequested{
 		Event:       SubjectVideoTranscribeRequested,
 		URL:         url,
 		JobID:       jobID,
 		RequestedAt: time.Now().UTC(),
+		JobOptions:  opts,
 	}
 	b, err := json.Marshal(ev)
 	if err != nil {
 		return "", err
 	}
-	if _, err := p.js.Publish(ctx, SubjectVideoTranscribeRequested, b); err != nil {
+	// Queued is recorded before the publish: written after, it could overwrite the
+	// "running" of a Dispatcher that was faster.
+	p.status.MarkQueued(jobID, url)
+	ack, err := p.js.Publish(ctx, SubjectVideoTranscribeRequested, b, jetstream.WithMsgID(msgID))
+	if err != nil {
+		p.status.MarkPublishFailed(jobID, fmt.Errorf("publish request: %w", err))
 		return "", err
 	}
+	if ack.Duplicate {
+		p.log.Info("duplicate VideoTranscribeRequested dropped by the stream", zap.String("job", jobID))
+		return jobID, nil
+	}
+	if idempotencyKey != "" {
+		p.storeIdempotencyKey(idempotencyKey, idempotencyRecord{JobID: jobID, URL: url})
+	}
 	p.log.Info("published VideoTranscribeRequested", zap.String("job", jobID), zap.String("url", url))
 	return jobID, nil
 }
 
-// generateJobID returns a reasonably unique identifier for jobs.
-func generateJobID() string {
-	return time.Now().UTC().Format("20060102T150405.000000000Z07:00")
+func (p *jsPublisher) lookupIdempotencyKey(key string) (*idempotencyRecord, error) {
+	b, err := p.store.Get(idempotencyStoreKey(key))
+	if err != nil || b == nil {
+		return nil, err
+	}
+	var rec idempotencyRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("decode idempotency record: %w", err)
+	}
+	return &rec, nil
+}
+
+// storeIdempotencyKey is best-effort: without the record a late retry still finds the
+// status of the job id derived from the key.
+func (p *jsPublisher) storeIdempotencyKey(key string, rec idempotencyRecord) {
+	b, err := json.Marshal(rec)
+	if err == nil {
+		err = p.store.Set(idempotencyStoreKey(key), b, idempotencyTTL)
+	}
+	if err != nil {
+		p.log.Warn("store idempotency key failed", zap.String("job", rec.JobID), zap.Error(err))
+	}
+}
+
+func idempotencyStoreKey(key string) string {
+	return "transcribe.idem." + kvSafe(key)
+}
+
+func (p *jsPublisher) PublishVideoTranscribeCancelRequested(ctx context.Context, jobID string) error {
+	// Flag first: a Dispatcher picking the job up concurrently will see it and skip.
+	p.status.RequestCancel(jobID)
+	ev := VideoTranscribeCancelRequested{
+		Event:       SubjectVideoTranscribeCancelRequested,
+		JobID:       jobID,
+		RequestedAt: time.Now().UTC(),
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if _, err := p.js.Publish(ctx, SubjectVideoTranscribeCancelRequested, b); err != nil {
+		return err
+	}
+	p.log.Info("published VideoTranscribeCancelRequested", zap.String("job", jobID))
+	return nil
 }
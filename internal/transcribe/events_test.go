@@ -0,0 +1,88 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeJS counts published messages; Publish fails while fail is set.
+type fakeJS struct {
+	jetstream.JetStream
+	fail      bool
+	published int
+}
+
+func (f *fakeJS) Publish(context.Context, string, []byte, ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	if f.fail {
+		return nil, errors.New("nats: no responders available for request")
+	}
+	f.published++
+	return &jetstream.PubAck{}, nil
+}
+
+// memKV is an in-memory kv.KVStore.
+type memKV struct {
+	mu   sync.Mutex
+	vals map[string][]byte
+}
+
+func (m *memKV) Get(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.vals[key], nil
+}
+
+func (m *memKV) Set(key string, val []byte, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.vals[key] = val
+	return nil
+}
+
+func (m *memKV) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.vals, key)
+	return nil
+}
+
+func (m *memKV) Reset() error { return nil }
+func (m *memKV) Close() error { return nil }
+
+func TestPublishRetriedAfterFailedPublish(t *testing.T) {
+	js := &fakeJS{fail: true}
+	status := &StatusRegistry{log: zap.NewNop(), kv: newMemCASStore()}
+	p := NewPublisher(js, &memKV{vals: map[string][]byte{}}, zap.NewNop(), status)
+	ctx := context.Background()
+	const url = "https://example.com/v.mp4"
+
+	_, err := p.PublishVideoTranscribeRequested(ctx, url, "", "key-1", JobOptions{})
+	require.Error(t, err)
+	assert.Zero(t, js.published)
+
+	// the client retries with the same key: the request is published this time
+	js.fail = false
+	jobID, err := p.PublishVideoTranscribeRequested(ctx, url, "", "key-1", JobOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, idempotentJobID(url, "key-1"), jobID)
+	assert.Equal(t, 1, js.published)
+
+	st, err := status.Get(jobID)
+	require.NoError(t, err)
+	assert.Equal(t, JobStateQueued, st.State)
+	assert.False(t, st.Unpublished)
+
+	// and a later retry is answered from the key record
+	again, err := p.PublishVideoTranscribeRequested(ctx, url, "", "key-1", JobOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, jobID, again)
+	assert.Equal(t, 1, js.published)
+}
@@ -0,0 +1,91 @@
+package transcribe
+
+import (
+	"context"
+	"strings"
+
+	"news-scrabber/internal/config"
+	"news-scrabber/internal/search/elasticsearch"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// rawContentIndex holds transcripts in languages without a dedicated index.
+const rawContentIndex = "raw-content"
+
+// IndexRouter picks the Elasticsearch index for a transcript by its language. Each language
+// with a configured analyzer gets a raw-content-<lang> index whose text fields use it, so
+// search stems in that language; everything else goes to raw-content.
+type IndexRouter struct {
+	es        *elasticsearch.Client
+	analyzers map[string]string
+	// translationAnalyzer is the analyzer of the translation target language, if configured.
+	translationAnalyzer string
+	log                 *zap.Logger
+}
+
+func NewIndexRouter(lc fx.Lifecycle, cfg *config.Config, es *elasticsearch.Client, log *zap.Logger) *IndexRouter {
+	r := &IndexRouter{
+		es:        es,
+		analyzers: make(map[string]string),
+		log:       log.With(zap.String("component", "transcribe.index")),
+	}
+	for lang, analyzer := range cfg.Elasticsearch.LanguageAnalyzers {
+		if lang = normalizeLanguage(lang); lang != "" && analyzer != "" {
+			r.analyzers[lang] = analyzer
+		}
+	}
+	r.translationAnalyzer = r.analyzers[normalizeLanguage(cfg.Translate.TargetLanguage)]
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			r.ensureIndexes(ctx)
+			return nil
+		},
+	})
+	return r
+}
+
+// Index returns the index for transcripts in lang.
+func (r *IndexRouter) Index(lang string) string {
+	lang = normalizeLanguage(lang)
+	if _, ok := r.analyzers[lang]; ok {
+		return rawContentIndex + "-" + lang
+	}
+	return rawContentIndex
+}
+
+// ensureIndexes creates the language indexes with their analyzers. It is best-effort:
+// an index that can't be created (e.g. the analyzer plugin is missing) is created by
+// Elasticsearch with default mappings on first write.
+func (r *IndexRouter) ensureIndexes(ctx context.Context) {
+	for lang, analyzer := range r.analyzers {
+		text := map[string]any{"type": "text", "analyzer": analyzer}
+		props := map[string]any{
+			"text": text,
+			"segments": map[string]any{
+				"properties": map[string]any{"text": text},
+			},
+		}
+		if r.translationAnalyzer != "" {
+			props["translation"] = map[string]any{"type": "text", "analyzer": r.translationAnalyzer}
+		}
+		body := map[string]any{"mappings": map[string]any{"properties": props}}
+		if err := r.es.EnsureIndex(ctx, r.Index(lang), body); err != nil {
+			r.log.Warn("ensure language index failed", zap.Error(err), zap.String("language", lang), zap.String("analyzer", analyzer))
+		}
+	}
+}
+
+// normalizeLanguage lowercases a language code and drops a region suffix ("en-US" -> "en").
+// "auto" and empty codes normalize to "".
+func normalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	if lang == "auto" {
+		return ""
+	}
+	return lang
+}
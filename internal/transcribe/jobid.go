@@ -0,0 +1,79 @@
+package transcribe
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs; lowercase keeps ids URL and key friendly
+// without changing their sort order.
+const crockford = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// maxJobIDPrefix bounds the source-derived part of a job id.
+const maxJobIDPrefix = 24
+
+// newJobID returns "<source>-<ulid>": a slug of the source host followed by a ULID, so ids of
+// the same source sort by creation time and never collide across instances.
+func newJobID(sourceURL string) string {
+	return jobIDPrefix(sourceURL) + "-" + newULID(time.Now())
+}
+
+// idempotentJobID returns "<source>-<hash>" for an Idempotency-Key: every retry of the
+// request gets the same id, so the stream deduplicates them by message id.
+func idempotentJobID(sourceURL, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return jobIDPrefix(sourceURL) + "-" + encodeBase32([16]byte(sum[:16]))
+}
+
+// newULID encodes a 48-bit millisecond timestamp and 80 random bits as 26 base32 characters.
+func newULID(t time.Time) string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(t.UnixMilli())<<16)
+	_, _ = rand.Read(b[6:]) // crypto/rand does not fail on supported platforms
+	return encodeBase32(b)
+}
+
+// encodeBase32 encodes 128 bits as 26 lowercase Crockford base32 characters.
+func encodeBase32(b [16]byte) string {
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	// 26*5 = 130 bits: the value is right-aligned, so the first character carries the top 3 bits.
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// jobIDPrefix derives a short slug from the source host ("www.youtube.com" -> "youtube-com");
+// sources without a host fall back to "job".
+func jobIDPrefix(sourceURL string) string {
+	host := ""
+	if u, err := url.Parse(strings.TrimSpace(sourceURL)); err == nil {
+		host = strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	}
+	var b strings.Builder
+	dash := false
+	for i := 0; i < len(host) && b.Len() < maxJobIDPrefix; i++ {
+		c := host[i]
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' {
+			b.WriteByte(c)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimRight(b.String(), "-")
+	if slug == "" {
+		return "job"
+	}
+	return slug
+}
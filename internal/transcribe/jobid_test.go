@@ -0,0 +1,46 @@
+package transcribe
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobIDPrefix(t *testing.T) {
+	cases := map[string]string{
+		"https://www.youtube.com/watch?v=abc":                       "youtube-com",
+		"rtmp://10.0.0.5:1935/live/stream":                          "10-0-0-5",
+		"https://a-very-long-subdomain.example-broadcaster.tv/live": "a-very-long-subdomain-ex",
+		"not a url": "job",
+		"":          "job",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, jobIDPrefix(in), in)
+	}
+}
+
+func TestNewULIDSortsByTime(t *testing.T) {
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	a := newULID(base)
+	b := newULID(base.Add(time.Millisecond))
+	assert.Len(t, a, 26)
+	assert.Less(t, a, b)
+	assert.NotEqual(t, newULID(base), newULID(base))
+	assert.Equal(t, strings.Trim(a, crockford), "")
+}
+
+func TestNewJobID(t *testing.T) {
+	id := newJobID("https://youtu.be/xyz")
+	assert.True(t, strings.HasPrefix(id, "youtu-be-"), id)
+	assert.Len(t, id, len("youtu-be-")+26)
+}
+
+func TestIdempotentJobID(t *testing.T) {
+	id := idempotentJobID("https://youtu.be/xyz", "client-key-1")
+	assert.Equal(t, id, idempotentJobID("https://youtu.be/xyz", "client-key-1"))
+	assert.NotEqual(t, id, idempotentJobID("https://youtu.be/xyz", "client-key-2"))
+	assert.True(t, strings.HasPrefix(id, "youtu-be-"), id)
+	assert.Len(t, id, len("youtu-be-")+26)
+}
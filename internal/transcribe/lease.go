@@ -0,0 +1,287 @@
+package transcribe
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"news-scrabber/internal/bootstrap"
+	"news-scrabber/internal/config"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// ErrLeaseLost is returned by Renew when another owner took the lease over.
+var ErrLeaseLost = errors.New("job lease lost")
+
+// LeaseHeldError reports that a live owner already holds the job or its source.
+type LeaseHeldError struct {
+	Kind   string // "job" or "source"
+	Holder LeaseRecord
+}
+
+func (e *LeaseHeldError) Error() string {
+	return fmt.Sprintf("%s lease held by %s (job %s)", e.Kind, e.Holder.Owner, e.Holder.JobID)
+}
+
+// LeaseRecord is the value stored under a lease key.
+type LeaseRecord struct {
+	JobID      string    `json:"job_id"`
+	SourceURL  string    `json:"source_url"`
+	Owner      string    `json:"owner"` // instance ID
+	AcquiredAt time.Time `json:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at"`
+}
+
+// LeaseManager grants cluster-wide ownership of running jobs. A job holds two leases in a
+// NATS KV bucket: one for its job ID and one for its normalized source URL. Keys expire
+// with the bucket TTL unless the owner renews them (every RenewInterval), so a crashed
+// owner frees the job.
+type LeaseManager struct {
+	js     jetstream.JetStream
+	bucket string
+	ttl    time.Duration
+	owner  string
+	log    *zap.Logger
+
+	mu sync.Mutex
+	kv casStore // set on start
+}
+
+// minLeaseTTL keeps the renew period (TTL/3) long enough for a KV round-trip.
+const minLeaseTTL = 3 * time.Second
+
+func NewLeaseManager(lc fx.Lifecycle, js jetstream.JetStream, cfg *config.Config, id bootstrap.InstanceID, log *zap.Logger) *LeaseManager {
+	ttl := time.Duration(cfg.Transcribe.LeaseTTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	if ttl < minLeaseTTL {
+		log.Warn("lease ttl too short, using minimum", zap.Duration("ttl", ttl), zap.Duration("min", minLeaseTTL))
+		ttl = minLeaseTTL
+	}
+	m := &LeaseManager{
+		js:     js,
+		bucket: cfg.JetStream.KVBucket + "_leases",
+		ttl:    ttl,
+		owner:  id.String(),
+		log:    log.With(zap.String("component", "transcribe.lease")),
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			_, err := m.store(ctx)
+			return err
+		},
+	})
+	return m
+}
+
+// TTL is how long a lease lives without renewal.
+func (m *LeaseManager) TTL() time.Duration {
+	return m.ttl
+}
+
+// RenewInterval is how often owners renew their leases: three tries per TTL.
+func (m *LeaseManager) RenewInterval() time.Duration {
+	return m.ttl / 3
+}
+
+// store returns the lease bucket, creating it on first use.
+func (m *LeaseManager) store(ctx context.Context) (casStore, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.kv != nil {
+		return m.kv, nil
+	}
+	kv, err := m.js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:  m.bucket,
+		TTL:     m.ttl,
+		Storage: jetstream.FileStorage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lease bucket %s: %w", m.bucket, err)
+	}
+	m.kv = jsCASStore{kv: kv}
+	return m.kv, nil
+}
+
+// Lease is a held job: both its job and source keys, with the revisions last written.
+type Lease struct {
+	m      *LeaseManager
+	kv     casStore
+	record LeaseRecord
+	keys   []string
+	revs   []uint64
+}
+
+// Acquire takes the job and source leases for this instance. It fails with *LeaseHeldError
+// when another live owner runs the job, or another job transcribes the same source.
+// A lease this instance already holds (e.g. a redelivered message) is taken over.
+func (m *LeaseManager) Acquire(ctx context.Context, jobID, sourceURL string) (*Lease, error) {
+	kv, err := m.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	l := &Lease{
+		m:      m,
+		kv:     kv,
+		record: LeaseRecord{JobID: jobID, SourceURL: sourceURL, Owner: m.owner, AcquiredAt: now, RenewedAt: now},
+	}
+	for _, k := range []struct{ kind, key string }{
+		{"job", jobLeaseKey(jobID)},
+		{"source", sourceLeaseKey(sourceURL)},
+	} {
+		rev, err := l.claim(ctx, k.kind, k.key)
+		if err != nil {
+			l.Release(context.Background())
+			return nil, err
+		}
+		l.keys = append(l.keys, k.key)
+		l.revs = append(l.revs, rev)
+	}
+	return l, nil
+}
+
+// claim creates key, or takes it over when this instance already owns it
+// for the same job.
+func (l *Lease) claim(ctx context.Context, kind, key string) (uint64, error) {
+	b, _ := json.Marshal(l.record)
+	rev, err := l.kv.Create(ctx, key, b)
+	if err == nil {
+		return rev, nil
+	}
+	if !errors.Is(err, jetstream.ErrKeyExists) {
+		return 0, err
+	}
+	value, rev, err := l.kv.Get(ctx, key)
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return l.kv.Create(ctx, key, b) // expired in between
+	}
+	if err != nil {
+		return 0, err
+	}
+	var holder LeaseRecord
+	if err := json.Unmarshal(value, &holder); err != nil || holder.Owner != l.record.Owner || holder.JobID != l.record.JobID {
+		return 0, &LeaseHeldError{Kind: kind, Holder: holder}
+	}
+	return l.kv.Update(ctx, key, b, rev)
+}
+
+// Renew extends both leases. ErrLeaseLost means another owner holds one of them now.
+func (l *Lease) Renew(ctx context.Context) error {
+	l.record.RenewedAt = time.Now().UTC()
+	b, _ := json.Marshal(l.record)
+	for i, key := range l.keys {
+		rev, err := l.kv.Update(ctx, key, b, l.revs[i])
+		if errors.Is(err, jetstream.ErrKeyExists) { // wrong last revision
+			return ErrLeaseLost
+		}
+		if err != nil {
+			return err
+		}
+		l.revs[i] = rev
+	}
+	return nil
+}
+
+// Release deletes the leases if they are still ours.
+func (l *Lease) Release(ctx context.Context) {
+	for i, key := range l.keys {
+		if err := l.kv.Delete(ctx, key, l.revs[i]); err != nil {
+			l.m.log.Debug("release lease failed", zap.String("key", key), zap.Error(err))
+		}
+	}
+	l.keys, l.revs = nil, nil
+}
+
+// SourceHolder returns the lease of the job currently transcribing sourceURL, or nil.
+func (m *LeaseManager) SourceHolder(ctx context.Context, sourceURL string) (*LeaseRecord, error) {
+	kv, err := m.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	value, _, err := kv.Get(ctx, sourceLeaseKey(sourceURL))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec LeaseRecord
+	if err := json.Unmarshal(value, &rec); err != nil {
+		return nil, fmt.Errorf("decode lease: %w", err)
+	}
+	return &rec, nil
+}
+
+func jobLeaseKey(jobID string) string {
+	return "job." + kvSafe(jobID)
+}
+
+func sourceLeaseKey(sourceURL string) string {
+	sum := sha256.Sum256([]byte(normalizeSourceURL(sourceURL)))
+	return "source." + hex.EncodeToString(sum[:16])
+}
+
+// normalizeSourceURL maps equivalent spellings of a source URL to one form: lowercase
+// scheme and host, no default port, fragment, trailing slash or tracking parameters,
+// and sorted query parameters. Unparseable input is only trimmed.
+func normalizeSourceURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") || port == "" {
+		u.Host = host
+	} else {
+		u.Host = host + ":" + port
+	}
+	u.Fragment, u.RawFragment = "", ""
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = ""
+	}
+
+	q := u.Query()
+	for k := range q {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(lk, "utm_") || lk == "fbclid" || lk == "gclid" {
+			q.Del(k)
+		}
+	}
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		vals := q[k]
+		sort.Strings(vals)
+		for _, v := range vals {
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(url.QueryEscape(k))
+			sb.WriteByte('=')
+			sb.WriteString(url.QueryEscape(v))
+		}
+	}
+	u.RawQuery = sb.String()
+	return u.String()
+}
@@ -0,0 +1,166 @@
+package transcribe
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memCASStore is an in-memory casStore with the same revision checks as NATS KV.
+type memCASStore struct {
+	mu   sync.Mutex
+	rev  uint64
+	vals map[string][]byte
+	revs map[string]uint64
+}
+
+func newMemCASStore() *memCASStore {
+	return &memCASStore{vals: map[string][]byte{}, revs: map[string]uint64{}}
+}
+
+func (s *memCASStore) put(key string, value []byte) uint64 {
+	s.rev++
+	s.vals[key], s.revs[key] = value, s.rev
+	return s.rev
+}
+
+func (s *memCASStore) Create(_ context.Context, key string, value []byte) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.vals[key]; ok {
+		return 0, jetstream.ErrKeyExists
+	}
+	return s.put(key, value), nil
+}
+
+func (s *memCASStore) Get(_ context.Context, key string) ([]byte, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.vals[key]
+	if !ok {
+		return nil, 0, jetstream.ErrKeyNotFound
+	}
+	return v, s.revs[key], nil
+}
+
+func (s *memCASStore) Update(_ context.Context, key string, value []byte, revision uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.revs[key] != revision {
+		return 0, jetstream.ErrKeyExists
+	}
+	return s.put(key, value), nil
+}
+
+func (s *memCASStore) Delete(_ context.Context, key string, revision uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.revs[key] != revision {
+		return jetstream.ErrKeyExists
+	}
+	delete(s.vals, key)
+	delete(s.revs, key)
+	return nil
+}
+
+// expire drops key like the bucket TTL does.
+func (s *memCASStore) expire(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.vals, key)
+	delete(s.revs, key)
+}
+
+func testLeaseManager(store casStore, owner string) *LeaseManager {
+	return &LeaseManager{ttl: 30 * time.Second, owner: owner, log: zap.NewNop(), kv: store}
+}
+
+func TestLeaseAcquireRenewRelease(t *testing.T) {
+	ctx := context.Background()
+	store := newMemCASStore()
+	a := testLeaseManager(store, "instance-a")
+	b := testLeaseManager(store, "instance-b")
+
+	lease, err := a.Acquire(ctx, "job-1", "https://live.example.com/ch1")
+	require.NoError(t, err)
+
+	// another instance can neither run the job nor transcribe the same source
+	_, err = b.Acquire(ctx, "job-1", "https://live.example.com/ch1")
+	var held *LeaseHeldError
+	require.ErrorAs(t, err, &held)
+	assert.Equal(t, "job", held.Kind)
+	assert.Equal(t, "instance-a", held.Holder.Owner)
+
+	_, err = b.Acquire(ctx, "job-2", "https://LIVE.example.com/ch1/")
+	require.ErrorAs(t, err, &held)
+	assert.Equal(t, "source", held.Kind)
+	assert.Equal(t, "job-1", held.Holder.JobID)
+	_, _, err = store.Get(ctx, jobLeaseKey("job-2"))
+	assert.ErrorIs(t, err, jetstream.ErrKeyNotFound, "a partial acquire is rolled back")
+
+	holder, err := b.SourceHolder(ctx, "https://live.example.com/ch1")
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", holder.JobID)
+
+	require.NoError(t, lease.Renew(ctx))
+	require.NoError(t, lease.Renew(ctx))
+
+	// the owner re-acquiring its own job (a redelivered message) takes the lease over
+	again, err := a.Acquire(ctx, "job-1", "https://live.example.com/ch1")
+	require.NoError(t, err)
+	assert.ErrorIs(t, lease.Renew(ctx), ErrLeaseLost, "the superseded lease is stale")
+
+	again.Release(ctx)
+	_, err = b.Acquire(ctx, "job-1", "https://live.example.com/ch1")
+	assert.NoError(t, err)
+}
+
+func TestLeaseLostAfterExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := newMemCASStore()
+	a := testLeaseManager(store, "instance-a")
+	b := testLeaseManager(store, "instance-b")
+
+	lease, err := a.Acquire(ctx, "job-1", "https://example.com/v.mp4")
+	require.NoError(t, err)
+
+	// a's renewals stalled past the TTL and b took the job over
+	store.expire(jobLeaseKey("job-1"))
+	store.expire(sourceLeaseKey("https://example.com/v.mp4"))
+	taken, err := b.Acquire(ctx, "job-1", "https://example.com/v.mp4")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, lease.Renew(ctx), ErrLeaseLost)
+
+	// releasing the stale lease must not delete the new owner's keys
+	lease.Release(ctx)
+	holder, err := a.SourceHolder(ctx, "https://example.com/v.mp4")
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+	assert.Equal(t, "instance-b", holder.Owner)
+	assert.NoError(t, taken.Renew(ctx))
+}
+
+func TestLeaseRenewInterval(t *testing.T) {
+	m := testLeaseManager(newMemCASStore(), "a")
+	assert.Equal(t, 10*time.Second, m.RenewInterval())
+}
+
+func TestNormalizeSourceURL(t *testing.T) {
+	same := []string{
+		"https://Live.Example.com:443/ch1/?b=2&a=1&utm_source=tg#player",
+		"https://live.example.com/ch1?a=1&b=2",
+		" https://live.example.com/ch1/?fbclid=x&b=2&a=1 ",
+	}
+	for _, u := range same {
+		assert.Equal(t, "https://live.example.com/ch1?a=1&b=2", normalizeSourceURL(u), u)
+	}
+	assert.Equal(t, "rtmp://live.example.com:1935/app/key", normalizeSourceURL("RTMP://LIVE.example.com:1935/app/key/"))
+	assert.NotEqual(t, sourceLeaseKey("https://a.example.com/1"), sourceLeaseKey("https://a.example.com/2"))
+}
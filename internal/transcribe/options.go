@@ -0,0 +1,109 @@
+package transcribe
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"news-scrabber/internal/config"
+	"news-scrabber/internal/transcribe/whisper"
+)
+
+// JobOptions are per-request transcription settings.
+// Zero values mean "use the configured default" (see Resolve).
+type JobOptions struct {
+	SegmentSeconds int    `json:"segment_seconds,omitempty"`
+	WindowSize     int    `json:"window_size,omitempty"`
+	Model          string `json:"model,omitempty"`
+	Language       string `json:"language,omitempty"` // ISO code or "auto"
+	BeamSize       int    `json:"beam_size,omitempty"`
+	// AudioStream is an ffmpeg stream specifier, e.g. "0:a:1" or "0:a:m:language:ukr".
+	// Empty lets ffmpeg pick the default audio stream.
+	AudioStream string `json:"audio_stream,omitempty"`
+	// OverlapSeconds of the previous chunk's audio are prepended to each chunk sent to
+	// Whisper; the duplicated text is trimmed by timestamps. 0 disables overlap mode.
+	OverlapSeconds int `json:"overlap_seconds,omitempty"`
+}
+
+// Validate rejects values outside sane bounds; zero values are always accepted.
+func (o JobOptions) Validate() error {
+	if o.SegmentSeconds != 0 && (o.SegmentSeconds < 5 || o.SegmentSeconds > 600) {
+		return fmt.Errorf("segment_seconds must be between 5 and 600")
+	}
+	if o.WindowSize != 0 && (o.WindowSize < 1 || o.WindowSize > 50) {
+		return fmt.Errorf("window_size must be between 1 and 50")
+	}
+	if o.BeamSize != 0 && (o.BeamSize < 1 || o.BeamSize > 10) {
+		return fmt.Errorf("beam_size must be between 1 and 10")
+	}
+	if o.OverlapSeconds < 0 || o.OverlapSeconds > 30 {
+		return fmt.Errorf("overlap_seconds must be between 0 and 30")
+	}
+	return nil
+}
+
+// Resolve fills unset fields from TranscribeConfig (and WhisperConfig for the model).
+func (o JobOptions) Resolve(cfg *config.Config) JobOptions {
+	if o.SegmentSeconds <= 0 {
+		o.SegmentSeconds = cfg.Transcribe.SegmentSeconds
+	}
+	if o.SegmentSeconds <= 0 {
+		o.SegmentSeconds = 60
+	}
+	if o.WindowSize <= 0 {
+		o.WindowSize = cfg.Transcribe.WindowSize
+	}
+	if o.WindowSize <= 0 {
+		o.WindowSize = 7
+	}
+	if o.Model == "" {
+		o.Model = cfg.Whisper.Model
+	}
+	if o.Language == "" {
+		o.Language = cfg.Transcribe.Language
+	}
+	if o.Language == "" {
+		o.Language = "auto"
+	}
+	if o.BeamSize <= 0 {
+		o.BeamSize = cfg.Transcribe.BeamSize
+	}
+	if o.BeamSize <= 0 {
+		o.BeamSize = 1
+	}
+	if o.AudioStream == "" {
+		o.AudioStream = cfg.Transcribe.AudioStream
+	}
+	if o.OverlapSeconds <= 0 {
+		o.OverlapSeconds = cfg.Transcribe.OverlapSeconds
+	}
+	if o.OverlapSeconds >= o.SegmentSeconds {
+		o.OverlapSeconds = o.SegmentSeconds / 2
+	}
+	return o
+}
+
+// sourceLanguage returns the language pinned for the source host (or a parent domain)
+// in TranscribeConfig.SourceLanguages, or "" when there is none.
+func sourceLanguage(cfg *config.Config, sourceURL string) string {
+	u, err := url.Parse(sourceURL)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	for {
+		if lang, ok := cfg.Transcribe.SourceLanguages[host]; ok {
+			return lang
+		}
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			return ""
+		}
+		host = host[i+1:]
+	}
+}
+
+// whisperOptions maps the job options onto a Whisper request.
+func (o JobOptions) whisperOptions() whisper.Options {
+	return whisper.Options{Model: o.Model, Language: o.Language, BeamSize: o.BeamSize}
+}
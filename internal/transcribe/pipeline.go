@@ -1,10 +1,12 @@
 package transcribe
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/exec"
@@ -23,11 +25,14 @@ import (
 	"news-scrabber/internal/search/elasticsearch"
 	"news-scrabber/internal/storage/s3client"
 	"news-scrabber/internal/transcribe/whisper"
+	"news-scrabber/internal/translate"
 	"news-scrabber/internal/vector/qdrant"
 )
 
-// RawContentReadyEvent is emitted to NATS on every processed 30s chunk.
-// It contains the latest chunk text and a rolling window of the previous 6 chunks (total ~3.5 minutes).
+// RawContentReadyEvent is emitted to NATS on every processed chunk.
+// It contains the latest chunk text and a rolling window over the last WindowSize chunks;
+// segment length and window size are set per request (JobOptions), defaulting to the
+// Transcribe config.
 // Subject: "news.RawContentReady"
 // NOTE: Keep this backward compatible; evolve by adding new json fields.
 type RawContentReadyEvent struct {
@@ -40,9 +45,40 @@ type RawContentReadyEvent struct {
 	WindowText   string    `json:"window_text"`
 	S3Key        string    `json:"s3_key"`
 	CreatedAt    time.Time `json:"created_at"`
+
+	// ChunkStartSeconds is the offset of the chunk from the start of the stream.
+	ChunkStartSeconds float64 `json:"chunk_start_seconds"`
+	// Segments carry Whisper segment timings as absolute stream offsets.
+	Segments []TimedSegment `json:"segments,omitempty"`
+	// DetectedLanguage is the spoken language of the chunk (ISO code); JobOptions.Language
+	// keeps what was requested, possibly "auto".
+	DetectedLanguage    string  `json:"detected_language,omitempty"`
+	LanguageProbability float64 `json:"language_probability,omitempty"`
+	// Translation is ChunkText in TranslationLanguage; absent when translation is off
+	// or the chunk already is in the target language.
+	Translation         string `json:"translation,omitempty"`
+	TranslationLanguage string `json:"translation_language,omitempty"`
+	// JobOptions reports the settings actually used for the job.
+	JobOptions
 }
 
-const segmentDurationSeconds = 60
+// TimedSegment is a transcript segment positioned on the source stream timeline (seconds).
+type TimedSegment struct {
+	Start        float64     `json:"start"`
+	End          float64     `json:"end"`
+	Text         string      `json:"text"`
+	AvgLogprob   float64     `json:"avg_logprob"`
+	NoSpeechProb float64     `json:"no_speech_prob"`
+	Words        []TimedWord `json:"words,omitempty"`
+}
+
+// TimedWord is a word positioned on the source stream timeline (seconds).
+type TimedWord struct {
+	Word        string  `json:"word"`
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Probability float64 `json:"probability"`
+}
 
 const rawContentReadySubject = "news.RawContentReady"
 
@@ -54,9 +90,16 @@ type JobParams struct {
 	JS  jetstream.JetStream
 	Log *zap.Logger
 	S3  *s3client.Client
-	WH  *whisper.Client
+	WH  whisper.Transcriber
 	ES  *elasticsearch.Client
 	Vec *qdrant.Client
+
+	Status      *StatusRegistry
+	Checkpoints *CheckpointStore
+	Resolver    *ResolverChain
+	Indexes     *IndexRouter
+	Translator  *translate.Client
+	Scheduler   *WhisperScheduler
 }
 
 // IngestJob coordinates ffmpeg segmentation and per-chunk processing.
@@ -64,26 +107,61 @@ type IngestJob struct {
 	jobID     string
 	sourceURL string
 	tempDir   string
+	opts      JobOptions // resolved: every field holds the value actually used
 
 	cfg *config.Config
 	js  jetstream.JetStream
 	log *zap.Logger
 	s3  *s3client.Client
-	wh  *whisper.Client
+	wh  whisper.Transcriber
 	es  *elasticsearch.Client
 	vec *qdrant.Client
 
+	status      *StatusRegistry
+	checkpoints *CheckpointStore
+	resolver    *ResolverChain
+	indexes     *IndexRouter
+	translator  *translate.Client
+	scheduler   *WhisperScheduler
+
+	// drain, once closed, stops ffmpeg and lets in-flight chunks finish (see Start).
+	drain <-chan struct{}
+
+	// codec encodes the archived copy of each chunk; nil keeps the WAV (see archiveAudio).
+	codec *audioCodec
+
+	// mediaURL is what ffmpeg opens: sourceURL after resolution (see resolveSource).
+	// Only the ffmpeg supervisor touches it once Start runs.
+	mediaURL string
+	// live is decided once in Start, before the supervisor and chunk workers run.
+	live bool
+
 	// internal
 	mu           sync.Mutex
-	chunkWindow  []string // last 7 chunk texts
+	chunkWindow  []string // last opts.WindowSize chunk texts
 	processedSet map[string]struct{}
+	lastEmitted  int     // last chunk index whose event was published; -1 for none
+	streamOffset float64 // seconds of source audio covered by emitted chunks
+	chunks       int     // chunks emitted, cumulative across resumes
+	failures     int     // chunks that failed processing, cumulative across resumes
+	// releasePending is the last emitted chunk whose files are released after the
+	// next chunk is emitted; -1 for none. releaseDurable tells how (see releaseChunk).
+	releasePending int
+	releaseDurable bool
+	// segmentStarts holds exact stream offsets of variable-length (silence-cut) segments.
+	segmentStarts map[int]float64
 }
 
-func NewIngestJob(params JobParams, jobID, sourceURL string) *IngestJob {
+func NewIngestJob(params JobParams, jobID, sourceURL string, opts JobOptions) *IngestJob {
+	if opts.Language == "" {
+		opts.Language = sourceLanguage(params.Cfg, sourceURL)
+	}
 	tempDir := filepath.Join(params.Cfg.Transcribe.TempDir, jobID)
+	codec, _ := archiveCodec(params.Cfg.Transcribe) // validated by NewService
 	return &IngestJob{
 		jobID:        jobID,
 		sourceURL:    sourceURL,
+		opts:         opts.Resolve(params.Cfg),
 		tempDir:      tempDir,
 		cfg:          params.Cfg,
 		js:           params.JS,
@@ -92,172 +170,855 @@ func NewIngestJob(params JobParams, jobID, sourceURL string) *IngestJob {
 		wh:           params.WH,
 		es:           params.ES,
 		vec:          params.Vec,
+		status:       params.Status,
+		checkpoints:  params.Checkpoints,
+		resolver:     params.Resolver,
+		indexes:      params.Indexes,
+		translator:   params.Translator,
+		scheduler:    params.Scheduler,
+		codec:        codec,
+		mediaURL:     sourceURL,
 		processedSet: make(map[string]struct{}),
+		lastEmitted:  -1,
+
+		releasePending: -1,
+
+		segmentStarts: make(map[int]float64),
+	}
+}
+
+// draining reports whether the job was asked to stop after its in-flight chunks.
+func (j *IngestJob) draining() bool {
+	select {
+	case <-j.drain:
+		return true
+	default:
+		return false
 	}
 }
 
-// Start launches ffmpeg segmenter and the watcher until context is done.
+// Start launches ffmpeg segmenter and the watcher. It returns when the context is done,
+// or once ffmpeg exited because the source ended and every remaining segment was handled;
+// in the latter case a JobCompleted event is published. Live sources are reconnected
+// whenever ffmpeg exits (see superviseFFmpeg); an ffmpeg error on any other source fails
+// the job, even after chunks were emitted.
 func (j *IngestJob) Start(ctx context.Context) error {
 	if err := os.MkdirAll(j.tempDir, 0o755); err != nil {
 		return err
 	}
+	_ = os.Remove(filepath.Join(j.tempDir, jobDoneMarker)) // a replayed job runs again
+	j.live = j.detectLive(ctx)
+	j.resume()
 
-	j.log.Info("starting ffmpeg segmenter", zap.String("url", j.sourceURL), zap.String("dir", j.tempDir))
-	segPattern := filepath.Join(j.tempDir, "segment_%05d.wav")
-	cmd := exec.CommandContext(ctx, j.cfg.Transcribe.FFmpegPath,
-		"-hide_banner", "-loglevel", "error",
-		"-i", j.sourceURL,
-		"-vn",
-		"-ac", "1",
-		"-ar", "16000",
-		"-c:a", "pcm_s16le",
-		"-f", "segment",
-		"-segment_time", strconv.Itoa(segmentDurationSeconds),
-		"-reset_timestamps", "1",
-		segPattern,
-	)
+	// Draining stops ffmpeg only; chunks already being processed still finish and checkpoint.
+	ffmpegCtx, stopFFmpeg := context.WithCancel(ctx)
+	defer stopFFmpeg()
+	go func() {
+		select {
+		case <-j.drain:
+			stopFFmpeg()
+		case <-ffmpegCtx.Done():
+		}
+	}()
+
+	completed := make(chan string, 16)
+	var ffmpegErr error // written before completed is closed
+	go func() {
+		defer close(completed)
+		ffmpegErr = j.superviseFFmpeg(ffmpegCtx, completed)
+	}()
 
-	// Start watcher first to not miss early files
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		j.watchAndProcess(ctx)
+		j.watchAndProcess(ctx, completed)
 	}()
 
-	// Run ffmpeg (will exit when ctx is canceled or source ends)
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("ffmpeg start: %w", err)
+	// The watcher returns on cancel, or after ffmpeg exited and the last segment was handled.
+	wg.Wait()
+	if ctx.Err() != nil {
+		j.log.Info("ingest job context done")
+		if errors.Is(context.Cause(ctx), ErrJobCancelled) {
+			// cancelled for good: nothing will resume from the dir. A shutdown or a
+			// requeue keeps it for the next run.
+			for range completed { // wait for ffmpeg to exit
+			}
+			j.cleanupJobDir()
+		}
+		return nil
 	}
-	go func() {
-		if err := cmd.Wait(); err != nil {
-			if ctx.Err() == nil { // log only if not due to context cancel
+	if j.draining() {
+		j.log.Info("ingest job drained", zap.Int("last_chunk", j.lastEmitted))
+		return ErrJobDrained
+	}
+	if ffmpegErr != nil {
+		// a file that broke off midway did not complete: fail, and the retry resumes
+		// from the checkpoint instead of publishing a truncated transcript
+		return fmt.Errorf("ffmpeg: %w", ffmpegErr)
+	}
+	j.cleanupJobDir()
+	j.publishCompleted(ctx)
+	return nil
+}
+
+// ErrJobDrained is returned by Start when the job stopped on its drain signal
+// after checkpointing its in-flight chunks; it did not complete.
+var ErrJobDrained = errors.New("ingest job drained")
+
+// ErrJobCancelled is the cancel cause of a job stopped on request (see Dispatcher.handleCancel).
+var ErrJobCancelled = errors.New("ingest job cancelled")
+
+// errLiveSourceEOF is the disconnect reason when ffmpeg exits cleanly on a live source.
+var errLiveSourceEOF = errors.New("live source closed the stream")
+
+// errReconnectGaveUp marks a live source that kept failing after all reconnect attempts.
+var errReconnectGaveUp = errors.New("live source reconnect attempts exhausted")
+
+// superviseFFmpeg runs ffmpeg until the source ends or ctx is done, forwarding finished
+// segment paths to out. When ffmpeg exits on a live source, cleanly or not, it is restarted
+// with exponential backoff; numbering continues after the last produced segment and the
+// stream offset skips the outage, so chunk offsets stay on the broadcast timeline.
+func (j *IngestJob) superviseFFmpeg(ctx context.Context, out chan<- string) error {
+	tc := j.cfg.Transcribe
+	live := j.live
+	minBackoff := time.Duration(tc.ReconnectBackoffSeconds) * time.Second
+	if minBackoff <= 0 {
+		minBackoff = 2 * time.Second
+	}
+	maxBackoff := time.Duration(tc.ReconnectMaxBackoffSeconds) * time.Second
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+
+	j.mu.Lock()
+	next, offset := j.lastEmitted+1, j.streamOffset
+	j.mu.Unlock()
+	seek := 0.0
+	if offset > 0 && !live {
+		// seekable source: skip audio already covered by emitted chunks
+		seek = offset
+	}
+
+	var (
+		backoff       = minBackoff
+		failures      int // consecutive failed runs
+		restarts      int
+		interruptedAt time.Time
+		interruptedOf float64 // stream offset when the source dropped
+	)
+	for {
+		if !interruptedAt.IsZero() {
+			offset = interruptedOf + time.Since(interruptedAt).Seconds()
+		}
+		runOut := make(chan string)
+		runErr := make(chan error, 1)
+		go func(start int, offset float64) {
+			defer close(runOut)
+			runErr <- j.runFFmpeg(ctx, start, offset, seek, runOut)
+		}(next, offset)
+
+		for path := range runOut {
+			if idx, err := parseIndex(path); err == nil {
+				j.mu.Lock()
+				start, ok := j.segmentStarts[idx]
+				if !ok { // fixed-length segments; silence-cut ones are recorded by the segmenter
+					start = offset
+					j.segmentStarts[idx] = start
+				}
+				j.mu.Unlock()
+				offset = start + j.wavDurationSeconds(path)
+				next = idx + 1
+			}
+			if !interruptedAt.IsZero() {
+				j.publishStreamResumed(ctx, next-1, restarts, interruptedAt)
+				interruptedAt = time.Time{}
+			}
+			failures, backoff = 0, minBackoff
+			select {
+			case out <- path:
+			case <-ctx.Done():
+			}
+		}
+		err := <-runErr
+		seek = 0 // only the first run resumes by seeking
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !live {
+			if err != nil {
 				j.log.Warn("ffmpeg exited with error", zap.Error(err))
+				return err
 			}
-		} else {
 			j.log.Info("ffmpeg finished")
+			return nil
 		}
-	}()
+		if err == nil {
+			// a live stream has no end: a clean exit is a dropped connection, unless the
+			// broadcast was an HLS event that has since been closed
+			if isHLSPlaylist(j.mediaURL) && j.resolver != nil && !j.resolver.IsLivePlaylist(ctx, j.mediaURL) {
+				j.log.Info("live playlist ended, ffmpeg finished")
+				return nil
+			}
+			err = errLiveSourceEOF
+		}
+		j.log.Warn("live source disconnected", zap.Error(err))
+		failures++
+		if failures > tc.ReconnectMaxAttempts {
+			j.log.Error("giving up on live source", zap.Error(err), zap.Int("attempts", failures-1))
+			return fmt.Errorf("%w: %v", errReconnectGaveUp, err)
+		}
+		if interruptedAt.IsZero() {
+			interruptedAt, interruptedOf = time.Now(), offset
+			j.publishStreamInterrupted(ctx, next-1, err, interruptedAt)
+		}
+		j.log.Info("reconnecting to live source",
+			zap.Int("attempt", failures),
+			zap.Duration("backoff", backoff),
+			zap.Int("next_chunk", next),
+		)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+		backoff = min(backoff*2, maxBackoff)
+		restarts++
+		if j.mediaURL != j.sourceURL {
+			// resolved stream URLs (e.g. from yt-dlp) expire; ask again before reconnecting
+			if err := j.resolveSource(ctx); err != nil {
+				j.log.Warn("re-resolve source failed, reusing previous media url", zap.Error(err))
+			}
+		}
+	}
+}
 
-	// Block until context canceled
-	<-ctx.Done()
-	j.log.Info("ingest job context done, waiting watcher")
-	wg.Wait()
+// runFFmpeg runs one ffmpeg process, numbering segments from startNumber and, in silence
+// mode, positioning them from offset. seek > 0 skips that much of a seekable source.
+func (j *IngestJob) runFFmpeg(ctx context.Context, startNumber int, offset, seek float64, out chan<- string) error {
+	j.log.Info("starting ffmpeg segmenter",
+		zap.String("url", j.mediaURL),
+		zap.String("dir", j.tempDir),
+		zap.Int("start_number", startNumber),
+	)
+	segPattern := filepath.Join(j.tempDir, "segment_%05d.wav")
+	args := []string{"-hide_banner", "-loglevel", "error"}
+	if seek > 0 {
+		args = append(args, "-ss", strconv.FormatFloat(seek, 'f', 3, 64))
+	}
+	args = append(args, "-i", j.mediaURL)
+	if j.opts.AudioStream != "" {
+		args = append(args, "-map", j.opts.AudioStream)
+	}
+	args = append(args,
+		"-vn",
+		"-ac", "1",
+		"-ar", "16000",
+		"-c:a", "pcm_s16le",
+	)
+	cutAtSilence := j.cfg.Transcribe.CutAtSilence
+	if cutAtSilence {
+		// raw PCM on stdout; silenceSegmenter picks the cut points
+		args = append(args, "-f", "s16le", "pipe:1")
+	} else {
+		args = append(args,
+			"-f", "segment",
+			"-segment_time", strconv.Itoa(j.opts.SegmentSeconds),
+			"-segment_start_number", strconv.Itoa(startNumber),
+			"-reset_timestamps", "1",
+			// ffmpeg prints each segment name to stdout once the segment is closed
+			"-segment_list", "pipe:1",
+			"-segment_list_type", "flat",
+			segPattern,
+		)
+	}
+	cmd := exec.CommandContext(ctx, j.cfg.Transcribe.FFmpegPath, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("ffmpeg stdout: %w", err)
+	}
+	// Run ffmpeg (will exit when ctx is canceled or source ends)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ffmpeg start: %w", err)
+	}
+
+	if cutAtSilence {
+		j.cutAtSilence(ctx, stdout, startNumber, offset, out)
+	} else {
+		j.readSegmentList(ctx, stdout, out)
+	}
+	// Wait only after stdout is drained (see exec.Cmd.StdoutPipe).
+	return cmd.Wait()
+}
+
+// resolveSource runs the resolver chain on the source URL and records the media URL
+// on the job status when it differs.
+func (j *IngestJob) resolveSource(ctx context.Context) error {
+	if j.resolver == nil {
+		return nil
+	}
+	media, err := j.resolver.Resolve(ctx, j.sourceURL)
+	if err != nil {
+		return fmt.Errorf("resolve source: %w", err)
+	}
+	j.mediaURL = media
+	if media != j.sourceURL {
+		j.status.MarkResolved(j.jobID, media)
+	}
 	return nil
 }
 
-func (j *IngestJob) watchAndProcess(ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+// publishStreamInterrupted emits StreamInterrupted for a live source that dropped.
+func (j *IngestJob) publishStreamInterrupted(ctx context.Context, lastChunk int, cause error, at time.Time) {
+	j.publishEvent(ctx, SubjectStreamInterrupted, StreamInterruptedEvent{
+		Event:          "StreamInterrupted",
+		JobID:          j.jobID,
+		SourceURL:      j.sourceURL,
+		LastChunkIndex: lastChunk,
+		Error:          cause.Error(),
+		InterruptedAt:  at.UTC(),
+	})
+}
+
+// publishStreamResumed emits StreamResumed once a reconnected source produced a segment.
+func (j *IngestJob) publishStreamResumed(ctx context.Context, chunk, restarts int, interruptedAt time.Time) {
+	now := time.Now()
+	j.log.Info("live source resumed", zap.Int("chunk", chunk), zap.Int("restarts", restarts))
+	j.publishEvent(ctx, SubjectStreamResumed, StreamResumedEvent{
+		Event:          "StreamResumed",
+		JobID:          j.jobID,
+		SourceURL:      j.sourceURL,
+		NextChunkIndex: chunk,
+		Restarts:       restarts,
+		GapSeconds:     now.Sub(interruptedAt).Seconds(),
+		InterruptedAt:  interruptedAt.UTC(),
+		ResumedAt:      now.UTC(),
+	})
+}
+
+// publishEvent marshals ev and publishes it best-effort.
+func (j *IngestJob) publishEvent(ctx context.Context, subject string, ev any) {
+	b, _ := json.Marshal(ev)
+	if _, err := j.js.Publish(ctx, subject, b); err != nil {
+		j.log.Warn("nats publish failed", zap.Error(err), zap.String("subject", subject))
+	}
+}
 
+// publishCompleted emits the JobCompleted event with the job totals.
+func (j *IngestJob) publishCompleted(ctx context.Context) {
+	ev := JobCompletedEvent{
+		Event:        "JobCompleted",
+		JobID:        j.jobID,
+		SourceURL:    j.sourceURL,
+		Chunks:       j.chunks,
+		Failures:     j.failures,
+		AudioSeconds: j.streamOffset,
+		CompletedAt:  time.Now().UTC(),
+	}
+	j.publishEvent(ctx, SubjectJobCompleted, ev)
+	j.log.Info("ingest job completed",
+		zap.Int("chunks", ev.Chunks),
+		zap.Int("failures", ev.Failures),
+		zap.Float64("audio_seconds", ev.AudioSeconds),
+	)
+}
+
+// resume restores progress from the job checkpoint. Local segments that were never
+// emitted are dropped: ffmpeg re-creates them from the resume point under the same numbers.
+func (j *IngestJob) resume() {
+	cp, err := j.checkpoints.Load(j.jobID)
+	if err != nil {
+		j.log.Warn("load checkpoint failed, starting from scratch", zap.Error(err))
+		return
+	}
+	if cp == nil {
+		return
+	}
+	j.lastEmitted = cp.LastChunkIndex
+	j.streamOffset = cp.StreamOffsetSeconds
+	j.chunks = cp.Chunks
+	j.failures = cp.Failures
+	j.chunkWindow = append([]string(nil), cp.Window...)
+
+	stale, _ := filepath.Glob(filepath.Join(j.tempDir, "segment_*.wav"))
+	for _, f := range stale {
+		idx, err := parseIndex(f)
+		switch {
+		case err != nil:
+		case idx > j.lastEmitted:
+			_ = os.Remove(f)
+			for _, ext := range append([]string{".txt", ".json"}, archiveExts...) {
+				_ = os.Remove(strings.TrimSuffix(f, ".wav") + ext)
+			}
+		case idx < j.lastEmitted:
+			// emitted before the restart but not released yet; whether it reached S3 is unknown
+			j.keepFailedChunk(idx)
+		default:
+			j.releasePending, j.releaseDurable = idx, false // overlap still reads it
+		}
+	}
+	j.log.Info("resuming job from checkpoint",
+		zap.Int("last_chunk", j.lastEmitted),
+		zap.Float64("offset_seconds", j.streamOffset),
+		zap.Int("window", len(j.chunkWindow)),
+	)
+}
+
+// saveCheckpoint persists progress after chunk idx was emitted or given up on.
+func (j *IngestJob) saveCheckpoint(idx int, seconds float64, failed bool) {
+	j.mu.Lock()
+	j.lastEmitted = idx
+	j.streamOffset += seconds
+	if failed {
+		j.failures++
+	} else {
+		j.chunks++
+	}
+	delete(j.segmentStarts, idx)
+	cp := JobCheckpoint{
+		JobID:               j.jobID,
+		LastChunkIndex:      j.lastEmitted,
+		Window:              append([]string(nil), j.chunkWindow...),
+		StreamOffsetSeconds: j.streamOffset,
+		Chunks:              j.chunks,
+		Failures:            j.failures,
+	}
+	j.mu.Unlock()
+	if err := j.checkpoints.Save(cp); err != nil {
+		j.log.Warn("save checkpoint failed", zap.Error(err), zap.Int("chunk", idx))
+	}
+}
+
+// readSegmentList forwards finished segment paths from ffmpeg's flat segment list.
+func (j *IngestJob) readSegmentList(ctx context.Context, r io.Reader, out chan<- string) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		name := strings.TrimSpace(sc.Text())
+		if name == "" {
+			continue
+		}
+		select {
+		case out <- filepath.Join(j.tempDir, filepath.Base(name)):
+		case <-ctx.Done():
+			return
+		}
+	}
+	if err := sc.Err(); err != nil {
+		j.log.Warn("read ffmpeg segment list failed", zap.Error(err))
+	}
+}
+
+// chunkResult is the outcome of the parallel part of chunk processing.
+type chunkResult struct {
+	idx        int
+	path       string
+	transcript *whisper.Transcript
+	s3Key      string
+	translated string // chunk text in Translate.TargetLanguage; empty when not translated
+	durable    bool   // every artifact reached S3 and Elasticsearch
+	err        error
+}
+
+// cutAtSilence segments ffmpeg's PCM output at natural pauses.
+func (j *IngestJob) cutAtSilence(ctx context.Context, r io.Reader, startIndex int, offset float64, out chan<- string) {
+	tc := j.cfg.Transcribe
+	seg := newSilenceSegmenter(j.tempDir, startIndex, offset,
+		j.opts.SegmentSeconds, tc.SilenceToleranceSeconds, tc.SilenceThresholdDB, tc.SilenceMinPauseMillis)
+	seg.onSegment = func(idx int, _ string, start float64) {
+		j.mu.Lock()
+		j.segmentStarts[idx] = start
+		j.mu.Unlock()
+	}
+	if err := seg.run(ctx, r, out); err != nil {
+		if ctx.Err() == nil {
+			j.log.Warn("silence segmenter failed", zap.Error(err))
+		}
+		// keep ffmpeg from blocking on a full pipe until it exits
+		_, _ = io.Copy(io.Discard, r)
+	}
+}
+
+// watchAndProcess handles segments as ffmpeg reports them complete. Up to
+// Transcribe.ChunkParallelism segments are transcribed at once, while events are
+// emitted strictly in chunk order. Once ffmpeg exits on its own, the remaining
+// segments on disk (e.g. the last one after an abrupt exit) are flushed as well.
+func (j *IngestJob) watchAndProcess(ctx context.Context, completed <-chan string) {
+	workers := j.cfg.Transcribe.ChunkParallelism
+	if workers <= 0 {
+		workers = 1
+	}
+
+	work := make(chan string)
+	results := make(chan chunkResult)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range work {
+				results <- j.prepareChunk(ctx, path)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	go j.feedSegments(ctx, completed, work)
+
+	pending := make(map[int]chunkResult)
+	for res := range results {
+		if ctx.Err() != nil {
+			// shutting down: in-flight chunks failed with the context and must be redone on resume
+			continue
+		}
+		if res.idx <= j.lastEmitted {
+			j.log.Debug("chunk already emitted before resume, skipping", zap.String("file", res.path))
+			continue
+		}
+		pending[res.idx] = res
+		j.emitReady(ctx, pending, false)
+	}
+	if ctx.Err() == nil {
+		j.emitReady(ctx, pending, true)
+	}
+}
+
+// feedSegments schedules every segment once: completed ones as reported by ffmpeg,
+// then whatever is left on disk after ffmpeg exited.
+func (j *IngestJob) feedSegments(ctx context.Context, completed <-chan string, work chan<- string) {
+	defer close(work)
+	send := func(path string) bool {
+		if _, ok := j.processedSet[path]; ok {
+			return true
+		}
+		select {
+		case work <- path:
+			j.processedSet[path] = struct{}{}
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			j.scanOnce(ctx)
+		case path, ok := <-completed:
+			if !ok {
+				// when draining, segments left on disk are recreated on resume instead
+				if ctx.Err() == nil && !j.draining() {
+					for _, f := range j.remainingSegments() {
+						if !send(f) {
+							return
+						}
+					}
+				}
+				return
+			}
+			if !send(path) {
+				return
+			}
 		}
 	}
 }
 
-func (j *IngestJob) scanOnce(ctx context.Context) {
+// remainingSegments lists segments in the job dir that were not scheduled yet.
+func (j *IngestJob) remainingSegments() []string {
+	j.mu.Lock()
+	lastEmitted := j.lastEmitted
+	j.mu.Unlock()
 	var files []string
 	filepath.WalkDir(j.tempDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return nil
 		}
 		if d.IsDir() {
+			if path != j.tempDir {
+				return filepath.SkipDir // e.g. uploaded/
+			}
 			return nil
 		}
-		if strings.HasPrefix(filepath.Base(path), "segment_") && strings.HasSuffix(path, ".wav") {
+		if idx, err := parseIndex(path); err == nil && idx > lastEmitted {
 			if _, ok := j.processedSet[path]; !ok {
 				files = append(files, path)
 			}
 		}
 		return nil
 	})
-	if len(files) == 0 {
-		return
-	}
 	sort.Strings(files)
-	for _, f := range files {
-		if err := j.processOne(ctx, f); err != nil {
-			j.log.Warn("process chunk failed", zap.String("file", f), zap.Error(err))
-			continue
+	return files
+}
+
+// emitReady emits pending results while they continue the chunk sequence.
+// With drain set, gaps are skipped so everything left is emitted in index order.
+func (j *IngestJob) emitReady(ctx context.Context, pending map[int]chunkResult, drain bool) {
+	for len(pending) > 0 {
+		next := j.lastEmitted + 1
+		res, ok := pending[next]
+		if !ok {
+			if !drain {
+				return
+			}
+			next = -1
+			for idx := range pending {
+				if next < 0 || idx < next {
+					next = idx
+				}
+			}
+			res = pending[next]
 		}
-		j.processedSet[f] = struct{}{}
+		delete(pending, next)
+		j.emitChunk(ctx, res)
 	}
 }
 
-func (j *IngestJob) processOne(ctx context.Context, path string) error {
+// prepareChunk runs the order-independent steps for one segment: uploads,
+// transcription and indexing. It is safe to call concurrently.
+func (j *IngestJob) prepareChunk(ctx context.Context, path string) chunkResult {
 	idx, err := parseIndex(path)
 	if err != nil {
-		return err
+		return chunkResult{idx: -1, path: path, err: err}
 	}
-	key := filepath.Join("raw", j.jobID, filepath.Base(path))
+	res := chunkResult{idx: idx, path: path}
+	res.err = j.processOne(ctx, &res)
+	return res
+}
 
-	// 1) Upload to S3 (placeholder client provides best-effort)
-	s3Key, err := j.s3.Upload(ctx, key, path)
-	if err != nil {
-		return fmt.Errorf("s3 upload: %w", err)
+// emitChunk runs the ordered steps for one chunk: rolling window, event and checkpoint.
+// A failed chunk leaves a gap in the events but still advances the checkpoint.
+func (j *IngestJob) emitChunk(ctx context.Context, res chunkResult) {
+	if res.err != nil {
+		j.log.Warn("process chunk failed", zap.String("file", res.path), zap.Error(res.err))
+		j.status.RecordError(j.jobID, fmt.Errorf("%s: %w", filepath.Base(res.path), res.err))
+		j.saveCheckpoint(res.idx, j.wavDurationSeconds(res.path), true)
+		j.releaseChunk(res.idx, false)
+		return
 	}
-	tf := filepath.Join(j.tempDir, fmt.Sprintf("segment_%05d.txt", idx))
-	// if file exists and not empty - assume already processed (e.g. from previous run) and skip re-transcription to save time and avoid duplicate events.
-	// This is a simple idempotency mechanism.
-	var text string
-	var textSaved bool
-	if info, err := os.Stat(tf); err == nil && info.Size() > 0 {
-		textBytes, err := os.ReadFile(tf)
+
+	// Emit NATS event with rolling window (last opts.WindowSize chunks)
+	window := j.appendAndWindow(res.transcript.Text, j.opts.WindowSize)
+	start := j.chunkStartSeconds(res.idx)
+	ev := RawContentReadyEvent{
+		Event:             "RawContentReady",
+		SourceURL:         j.sourceURL,
+		JobID:             j.jobID,
+		ChunkIndex:        res.idx,
+		ChunkSeconds:      j.opts.SegmentSeconds,
+		ChunkStartSeconds: start,
+		ChunkText:         res.transcript.Text,
+		WindowText:        window,
+		Segments:          absoluteSegments(res.transcript.Segments, start),
+		S3Key:             res.s3Key,
+		CreatedAt:         time.Now().UTC(),
+		JobOptions:        j.opts,
+
+		DetectedLanguage:    j.transcriptLanguage(res.transcript),
+		LanguageProbability: res.transcript.LanguageProbability,
+		Translation:         res.translated,
+		TranslationLanguage: j.translationLanguage(res.translated),
+	}
+	b, _ := json.Marshal(ev)
+	if _, err := j.js.Publish(ctx, rawContentReadySubject, b); err != nil {
+		j.log.Warn("nats publish failed", zap.Error(err))
+	}
+	j.saveCheckpoint(res.idx, j.wavDurationSeconds(res.path), false)
+	j.status.ChunkProcessed(j.jobID, res.idx)
+	j.releaseChunk(res.idx, res.durable)
+}
+
+// releaseChunk frees the local files of the previously emitted chunk and queues chunk idx.
+// Release lags one chunk because overlap mode still reads the previous segment while the
+// next one is transcribed. A chunk that is not durable is kept (see keepFailedChunk).
+func (j *IngestJob) releaseChunk(idx int, durable bool) {
+	j.flushRelease()
+	j.releasePending, j.releaseDurable = idx, durable
+}
+
+// flushRelease releases the queued chunk, if any.
+func (j *IngestJob) flushRelease() {
+	if j.releasePending < 0 {
+		return
+	}
+	if j.releaseDurable {
+		j.removeLocalChunk(j.releasePending)
+	} else {
+		j.keepFailedChunk(j.releasePending)
+	}
+	j.releasePending = -1
+}
+
+// removeLocalChunk deletes the segment files of a durable chunk idx, or with
+// Transcribe.RetentionSeconds set moves them to the uploaded/ subdirectory where DiskGuard
+// prunes them later.
+func (j *IngestJob) removeLocalChunk(idx int) {
+	switch retention := j.cfg.Transcribe.RetentionSeconds; {
+	case retention < 0:
+	case retention > 0:
+		j.moveChunkFiles(idx, uploadedDir)
+	default:
+		for _, f := range j.chunkFiles(idx) {
+			if err := os.Remove(f); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				j.log.Warn("release local chunk file failed", zap.Error(err), zap.String("file", f))
+			}
+		}
+	}
+}
+
+// keepFailedChunk moves the files of chunk idx, which failed or missed S3 or Elasticsearch,
+// to the failed/ subdirectory. The checkpoint moved past it, but its audio may exist nowhere
+// else; DiskGuard prunes it after Transcribe.FailedRetentionSeconds.
+func (j *IngestJob) keepFailedChunk(idx int) {
+	j.moveChunkFiles(idx, failedDir)
+}
+
+// chunkFiles lists every file chunk idx may have in the job dir.
+func (j *IngestJob) chunkFiles(idx int) []string {
+	var files []string
+	for _, ext := range append([]string{".wav", ".txt", ".json"}, archiveExts...) {
+		files = append(files, filepath.Join(j.tempDir, fmt.Sprintf("segment_%05d%s", idx, ext)))
+	}
+	return files
+}
+
+// moveChunkFiles moves the files of chunk idx to the sub directory of the job dir.
+func (j *IngestJob) moveChunkFiles(idx int, sub string) {
+	dir := filepath.Join(j.tempDir, sub)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		j.log.Warn("create chunk keep dir failed", zap.Error(err), zap.String("dir", dir))
+		return
+	}
+	now := time.Now()
+	for _, f := range j.chunkFiles(idx) {
+		to := filepath.Join(dir, filepath.Base(f))
+		err := os.Rename(f, to)
 		if err == nil {
-			text = string(textBytes)
-			textSaved = true
-			j.log.Info("chunk already processed, skipping transcription", zap.String("file", path))
-		} else {
-			j.log.Warn("read existing txt failed, will re-transcribe", zap.String("file", tf), zap.Error(err))
+			// retention counts from the release, not from when ffmpeg wrote the file
+			err = os.Chtimes(to, now, now)
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			j.log.Warn("release local chunk file failed", zap.Error(err), zap.String("file", f))
 		}
 	}
+}
+
+// jobDoneMarker marks the dir of a finished job that still holds kept chunk files
+// (uploaded/, failed/); DiskGuard removes the dir once they are pruned.
+const jobDoneMarker = ".done"
 
-	if !textSaved {
+// cleanupJobDir releases the last chunk and removes the job dir of a completed or cancelled
+// job. Kept chunk files stay until DiskGuard prunes them, and the dir with them.
+func (j *IngestJob) cleanupJobDir() {
+	j.flushRelease()
+	if j.cfg.Transcribe.RetentionSeconds < 0 {
+		return
+	}
+	entries, _ := os.ReadDir(j.tempDir)
+	for _, e := range entries {
+		switch p := filepath.Join(j.tempDir, e.Name()); e.Name() {
+		case uploadedDir, failedDir:
+			_ = os.Remove(p) // only when empty
+		default:
+			_ = os.RemoveAll(p)
+		}
+	}
+	if err := os.Remove(j.tempDir); err == nil || errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(j.tempDir, jobDoneMarker), nil, 0o644); err != nil {
+		j.log.Warn("mark job dir done failed", zap.Error(err), zap.String("dir", j.tempDir))
+	}
+}
+
+// processOne uploads, transcribes, translates and indexes the segment of res and fills in
+// its transcript, S3 key and translation. res.durable reports whether the best-effort uploads
+// and indexing succeeded as well, so the local files may be released.
+func (j *IngestJob) processOne(ctx context.Context, res *chunkResult) error {
+	idx, path := res.idx, res.path
+	archive := j.archiveAudio(ctx, path)
+	key := filepath.Join("raw", j.jobID, filepath.Base(archive))
+
+	// 1) Upload raw audio to S3, encoded with the archive codec when one is configured
+	s3Key, err := j.s3.Upload(ctx, key, archive)
+	if err != nil {
+		return fmt.Errorf("s3 upload: %w", err)
+	}
+	tf := filepath.Join(j.tempDir, fmt.Sprintf("segment_%05d.txt", idx))
+	jf := filepath.Join(j.tempDir, fmt.Sprintf("segment_%05d.json", idx))
+	// if a transcript exists and is not empty - assume already processed (e.g. from previous run) and skip re-transcription to save time and avoid duplicate events.
+	// This is a simple idempotency mechanism.
+	tr := j.readCachedTranscript(jf, tf)
+	if tr != nil {
+		j.log.Info("chunk already processed, skipping transcription", zap.String("file", path))
+	} else {
 		// 2) Transcribe with Whisper (with retry/backoff to survive transient cancellations)
-		text, err = j.transcribeWithRetry(ctx, path)
+		audio := path
+		if j.cfg.Whisper.UploadCompressed {
+			audio = archive
+		}
+		tr, err = j.transcribeChunk(ctx, idx, path, audio)
 		if err != nil {
 			return fmt.Errorf("whisper: %w", err)
 		}
 
-		if err := os.WriteFile(tf, []byte(text), 0o644); err != nil {
+		if err := os.WriteFile(tf, []byte(tr.Text), 0o644); err != nil {
 			j.log.Warn("write txt temp failed", zap.Error(err))
-		} else {
-			j.log.Info("transcription saved to temp file", zap.String("file", tf))
-			textSaved = true
+		}
+		if b, err := json.Marshal(tr); err == nil {
+			if err := os.WriteFile(jf, b, 0o644); err != nil {
+				j.log.Warn("write transcript json temp failed", zap.Error(err))
+			} else {
+				j.log.Info("transcription saved to temp file", zap.String("file", jf))
+			}
 		}
 	}
 
-	// 3) Upload transcribed text to S3 as well
-	textKey := filepath.Join("raw", j.jobID, fmt.Sprintf("segment_%05d.txt", idx))
-	// write to temp file then upload via placeholder client
-	if textSaved {
-		if _, err := j.s3.Upload(ctx, textKey, tf); err != nil {
-			j.log.Warn("s3 upload txt failed", zap.Error(err))
-		}
+	// 3) Upload transcribed text and the timed transcript to S3 as well
+	durable := true
+	textKey := filepath.Join("raw", j.jobID, filepath.Base(tf))
+	if _, err := j.s3.Upload(ctx, textKey, tf); err != nil {
+		j.log.Warn("s3 upload txt failed", zap.Error(err))
+		durable = false
+	}
+	if _, err := j.s3.Upload(ctx, filepath.Join("raw", j.jobID, filepath.Base(jf)), jf); err != nil {
+		j.log.Warn("s3 upload transcript json failed", zap.Error(err))
+		durable = false
 	}
 
-	// 4) Save text to Elasticsearch
+	// 4) Translate for analysts (optional, best-effort)
+	lang := j.transcriptLanguage(tr)
+	translated := j.translate(ctx, tr.Text, lang)
+
+	// 5) Save text to Elasticsearch, in the index of the spoken language
+	start := j.chunkStartSeconds(idx)
 	doc := map[string]any{
-		"job_id":        j.jobID,
-		"source_url":    j.sourceURL,
-		"chunk_index":   idx,
-		"chunk_seconds": 30,
-		"text":          text,
-		"s3_key":        s3Key,
-		"text_s3_key":   textKey,
-		"created_at":    time.Now().UTC().Format(time.RFC3339Nano),
-	}
-	if err := j.es.IndexText(ctx, "raw-content", fmt.Sprintf("%s-%05d", j.jobID, idx), doc); err != nil {
+		"job_id":               j.jobID,
+		"source_url":           j.sourceURL,
+		"chunk_index":          idx,
+		"chunk_seconds":        j.opts.SegmentSeconds,
+		"window_size":          j.opts.WindowSize,
+		"model":                j.opts.Model,
+		"language":             j.opts.Language,
+		"detected_language":    lang,
+		"language_probability": tr.LanguageProbability,
+		"beam_size":            j.opts.BeamSize,
+		"chunk_start_seconds":  start,
+		"text":                 tr.Text,
+		"translation":          translated,
+		"translation_language": j.translationLanguage(translated),
+		"segments":             absoluteSegments(tr.Segments, start),
+		"s3_key":               s3Key,
+		"text_s3_key":          textKey,
+		"created_at":           time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if err := j.es.IndexText(ctx, j.indexes.Index(lang), fmt.Sprintf("%s-%05d", j.jobID, idx), doc); err != nil {
 		j.log.Warn("elasticsearch index failed", zap.Error(err))
+		durable = false
 	}
 
-	// 4) Upsert into Qdrant (placeholder: may be no-op)
-	if err := j.vec.UpsertText(ctx, "raw-content", fmt.Sprintf("%s-%05d", j.jobID, idx), text, map[string]any{
+	// 6) Upsert into Qdrant (placeholder: may be no-op)
+	if err := j.vec.UpsertText(ctx, "raw-content", fmt.Sprintf("%s-%05d", j.jobID, idx), tr.Text, map[string]any{
 		"job_id":      j.jobID,
 		"chunk_index": idx,
 		"source_url":  j.sourceURL,
@@ -265,26 +1026,91 @@ func (j *IngestJob) processOne(ctx context.Context, path string) error {
 		j.log.Warn("qdrant upsert failed", zap.Error(err))
 	}
 
-	// 5) Emit NATS event with rolling window (last 7 chunks)
-	window := j.appendAndWindow(text, 7)
-	ev := RawContentReadyEvent{
-		Event:        "RawContentReady",
-		SourceURL:    j.sourceURL,
-		JobID:        j.jobID,
-		ChunkIndex:   idx,
-		ChunkSeconds: 30,
-		ChunkText:    text,
-		WindowText:   window,
-		S3Key:        s3Key,
-		CreatedAt:    time.Now().UTC(),
+	res.transcript, res.s3Key, res.translated, res.durable = tr, s3Key, translated, durable
+	return nil
+}
+
+// translate returns text in the configured target language, or "" when translation is
+// off, the text already is in that language, or the request failed.
+func (j *IngestJob) translate(ctx context.Context, text, lang string) string {
+	if j.translator == nil || !j.translator.Enabled() || strings.TrimSpace(text) == "" {
+		return ""
 	}
-	b, _ := json.Marshal(ev)
-	if _, err := j.js.Publish(ctx, rawContentReadySubject, b); err != nil {
-		j.log.Warn("nats publish failed", zap.Error(err))
+	if lang != "" && lang == normalizeLanguage(j.translator.TargetLanguage) {
+		return ""
+	}
+	out, err := j.translator.Translate(ctx, text, lang)
+	if err != nil {
+		j.log.Warn("translation failed", zap.Error(err))
+		return ""
+	}
+	return out
+}
+
+// translationLanguage is the target language when a translation was made, else "".
+func (j *IngestJob) translationLanguage(translated string) string {
+	if translated == "" {
+		return ""
+	}
+	return j.translator.TargetLanguage
+}
+
+// transcribeChunk transcribes a segment: audio is what Whisper receives, path the segment WAV
+// (audio may be its archive encoding). In overlap mode the tail of the previous segment is
+// prepended to the WAV and the duplicated text is trimmed from the result.
+func (j *IngestJob) transcribeChunk(ctx context.Context, idx int, path, audio string) (*whisper.Transcript, error) {
+	prev := filepath.Join(j.tempDir, fmt.Sprintf("segment_%05d.wav", idx-1))
+	if j.opts.OverlapSeconds <= 0 || idx == 0 {
+		return j.transcribeWithRetry(ctx, audio)
+	}
+	if _, err := os.Stat(prev); err != nil {
+		return j.transcribeWithRetry(ctx, audio)
+	}
+
+	// not named segment_*: the segment scanner must never pick it up
+	joined := filepath.Join(j.tempDir, fmt.Sprintf("overlap_%05d.wav", idx))
+	defer os.Remove(joined)
+	overlap, err := writeOverlapWAV(joined, prev, path, float64(j.opts.OverlapSeconds))
+	if err != nil {
+		j.log.Warn("build overlap audio failed, transcribing without overlap", zap.Error(err), zap.String("file", path))
+		return j.transcribeWithRetry(ctx, audio)
+	}
+	tr, err := j.transcribeWithRetry(ctx, joined)
+	if err != nil {
+		return nil, err
+	}
+	return trimOverlap(tr, overlap), nil
+}
+
+// readCachedTranscript loads a transcript left by a previous run: the timed JSON when
+// present, else the plain text. It returns nil when the chunk still needs transcription.
+func (j *IngestJob) readCachedTranscript(jsonPath, textPath string) *whisper.Transcript {
+	if b, err := os.ReadFile(jsonPath); err == nil && len(b) > 0 {
+		var tr whisper.Transcript
+		if err := json.Unmarshal(b, &tr); err == nil {
+			return &tr
+		}
+		j.log.Warn("decode existing transcript failed, falling back to txt", zap.String("file", jsonPath), zap.Error(err))
+	}
+	if info, err := os.Stat(textPath); err == nil && info.Size() > 0 {
+		b, err := os.ReadFile(textPath)
+		if err == nil {
+			return &whisper.Transcript{Text: string(b)}
+		}
+		j.log.Warn("read existing txt failed, will re-transcribe", zap.String("file", textPath), zap.Error(err))
 	}
 	return nil
 }
 
+// transcriptLanguage is the language Whisper reported for tr, or the pinned job language
+// for transcripts without one (e.g. cached plain text).
+func (j *IngestJob) transcriptLanguage(tr *whisper.Transcript) string {
+	if lang := normalizeLanguage(tr.Language); lang != "" {
+		return lang
+	}
+	return normalizeLanguage(j.opts.Language)
+}
+
 func (j *IngestJob) appendAndWindow(text string, n int) string {
 	j.mu.Lock()
 	defer j.mu.Unlock()
@@ -306,52 +1132,98 @@ func parseIndex(path string) (int, error) {
 	return i, err
 }
 
-// transcribeWithRetry wraps the whisper call with a bounded retry/backoff for transient issues
-// like context cancellations or connection resets from the Whisper server. It respects the
-// parent context and a per-request timeout based on Whisper config.
-func (j *IngestJob) transcribeWithRetry(ctx context.Context, path string) (string, error) {
-	maxAttempts := 3
-	backoff := 2 * time.Second
-
-	to := time.Duration(j.cfg.Whisper.TimeoutSeconds) * time.Second
-	if to <= 0 {
-		to = 600 * time.Second
+// chunkStartSeconds is the stream offset of chunk idx as recorded when the segment was
+// produced, or nominal (index × segment length) for segments found on disk afterwards.
+func (j *IngestJob) chunkStartSeconds(idx int) float64 {
+	j.mu.Lock()
+	start, ok := j.segmentStarts[idx]
+	j.mu.Unlock()
+	if ok {
+		return start
 	}
+	return float64(idx * j.opts.SegmentSeconds)
+}
 
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		if ctx.Err() != nil {
-			return "", ctx.Err()
-		}
-		cctx, cancel := context.WithTimeout(ctx, to)
-		text, err := j.wh.TranscribeFile(cctx, path)
-		cancel()
-		if err == nil {
-			return text, nil
+// absoluteSegments shifts chunk-relative Whisper timings by the chunk start offset.
+func absoluteSegments(segs []whisper.Segment, offset float64) []TimedSegment {
+	if len(segs) == 0 {
+		return nil
+	}
+	out := make([]TimedSegment, 0, len(segs))
+	for _, s := range segs {
+		ts := TimedSegment{
+			Start:        offset + s.Start,
+			End:          offset + s.End,
+			Text:         s.Text,
+			AvgLogprob:   s.AvgLogprob,
+			NoSpeechProb: s.NoSpeechProb,
 		}
-		if attempt == maxAttempts || !isRetryableWhisperErr(err) {
-			return "", err
-		}
-		j.log.Warn("whisper transient error, retrying", zap.Error(err), zap.Int("attempt", attempt), zap.String("file", path))
-		select {
-		case <-ctx.Done():
-			return "", ctx.Err()
-		case <-time.After(backoff):
-			backoff *= 2
+		for _, w := range s.Words {
+			ts.Words = append(ts.Words, TimedWord{
+				Word:        w.Word,
+				Start:       offset + w.Start,
+				End:         offset + w.End,
+				Probability: w.Probability,
+			})
 		}
+		out = append(out, ts)
 	}
-	return "", errors.New("unreachable")
+	return out
 }
 
-func isRetryableWhisperErr(err error) bool {
-	if err == nil {
-		return false
+// wavDurationSeconds returns the duration of a segment WAV,
+// falling back to the nominal segment length when the file can't be read.
+func (j *IngestJob) wavDurationSeconds(path string) float64 {
+	secs, err := wavSeconds(path)
+	if err != nil || secs <= 0 {
+		return float64(j.opts.SegmentSeconds)
 	}
-	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-		return true
+	return secs
+}
+
+// priority is the WhisperScheduler class of the job's chunks.
+func (j *IngestJob) priority() Priority {
+	if j.live {
+		return PriorityLive
 	}
-	msg := strings.ToLower(err.Error())
-	if strings.Contains(msg, "connection reset") || strings.Contains(msg, "broken pipe") {
+	return PriorityBackfill
+}
+
+// detectLive decides whether the job's source is a live stream that cannot be seeked:
+// a live protocol, or an HLS playlist that is still growing (see IsLivePlaylist).
+// HLS VOD is seekable, so a resumed job continues where it stopped.
+func (j *IngestJob) detectLive(ctx context.Context) bool {
+	if isLiveProtocol(j.mediaURL) || isLiveProtocol(j.sourceURL) {
 		return true
 	}
+	for _, u := range []string{j.mediaURL, j.sourceURL} {
+		if !isHLSPlaylist(u) {
+			continue
+		}
+		if j.resolver == nil {
+			return true
+		}
+		return j.resolver.IsLivePlaylist(ctx, u)
+	}
 	return false
 }
+
+// isLiveProtocol reports whether the URL uses a streaming protocol that is always live.
+func isLiveProtocol(url string) bool {
+	u := strings.ToLower(url)
+	for _, p := range []string{"rtmp://", "rtmps://", "rtsp://", "srt://", "udp://", "rtp://"} {
+		if strings.HasPrefix(u, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHLSPlaylist reports whether the URL path is an HLS playlist.
+func isHLSPlaylist(url string) bool {
+	u := strings.ToLower(url)
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	return strings.HasSuffix(u, ".m3u8")
+}
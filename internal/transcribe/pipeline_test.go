@@ -0,0 +1,74 @@
+package transcribe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"news-scrabber/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testChunkJob(t *testing.T, retention int) *IngestJob {
+	cfg := &config.Config{Transcribe: config.TranscribeConfig{RetentionSeconds: retention}}
+	return &IngestJob{cfg: cfg, log: zap.NewNop(), tempDir: filepath.Join(t.TempDir(), "job-1"), releasePending: -1}
+}
+
+func writeChunkFiles(t *testing.T, dir string, idx int) {
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	for _, ext := range []string{".wav", ".txt", ".json"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("segment_%05d%s", idx, ext)), []byte("x"), 0o644))
+	}
+}
+
+func TestReleaseFailedChunkKept(t *testing.T) {
+	j := testChunkJob(t, 0)
+	writeChunkFiles(t, j.tempDir, 0)
+	writeChunkFiles(t, j.tempDir, 1)
+
+	j.releaseChunk(0, false) // failed chunk
+	assert.FileExists(t, filepath.Join(j.tempDir, "segment_00000.wav"), "kept until the next chunk for overlap")
+	j.releaseChunk(1, true)
+	assert.FileExists(t, filepath.Join(j.tempDir, failedDir, "segment_00000.wav"))
+	assert.FileExists(t, filepath.Join(j.tempDir, failedDir, "segment_00000.txt"))
+
+	j.cleanupJobDir()
+	assert.NoFileExists(t, filepath.Join(j.tempDir, "segment_00001.wav"))
+	assert.NoDirExists(t, filepath.Join(j.tempDir, uploadedDir))
+	assert.FileExists(t, filepath.Join(j.tempDir, failedDir, "segment_00000.wav"))
+	assert.FileExists(t, filepath.Join(j.tempDir, jobDoneMarker))
+
+	// uploaded/ retention does not apply to failed chunks
+	g := &DiskGuard{dir: filepath.Dir(j.tempDir), retention: 1, log: zap.NewNop()}
+	g.pruneKept()
+	assert.FileExists(t, filepath.Join(j.tempDir, failedDir, "segment_00000.wav"))
+
+	g.failedRetention = 1
+	g.pruneKept()
+	assert.NoDirExists(t, j.tempDir)
+}
+
+func TestReleaseChunkRetained(t *testing.T) {
+	j := testChunkJob(t, 3600)
+	writeChunkFiles(t, j.tempDir, 0)
+	writeChunkFiles(t, j.tempDir, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(j.tempDir, "segment_00002.wav"), nil, 0o644)) // cut short
+
+	j.releaseChunk(0, true)
+	j.releaseChunk(1, true)
+	assert.FileExists(t, filepath.Join(j.tempDir, uploadedDir, "segment_00000.wav"))
+
+	j.cleanupJobDir()
+	assert.FileExists(t, filepath.Join(j.tempDir, uploadedDir, "segment_00001.json"))
+	assert.NoFileExists(t, filepath.Join(j.tempDir, "segment_00002.wav"))
+	assert.FileExists(t, filepath.Join(j.tempDir, jobDoneMarker))
+
+	// once the retention passed, DiskGuard prunes the files and the completed job dir
+	g := &DiskGuard{dir: filepath.Dir(j.tempDir), retention: 1, log: zap.NewNop()}
+	g.pruneKept()
+	assert.NoDirExists(t, j.tempDir)
+}
@@ -0,0 +1,152 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"news-scrabber/internal/config"
+
+	"go.uber.org/zap"
+)
+
+// SourceResolver turns a submitted URL into a media URL ffmpeg can open.
+// A resolver that does not handle the URL returns it unchanged.
+type SourceResolver interface {
+	Name() string
+	Resolve(ctx context.Context, rawURL string) (string, error)
+}
+
+// ResolverChain runs the configured resolvers in order, each on the previous result:
+// a YouTube link becomes a stream URL, an article page its embedded player source,
+// and an HLS master playlist its lowest-bitrate variant with audio.
+type ResolverChain struct {
+	resolvers []SourceResolver
+	timeout   time.Duration
+	http      *http.Client
+	userAgent string
+	log       *zap.Logger
+}
+
+func NewResolverChain(cfg *config.Config, log *zap.Logger) *ResolverChain {
+	tc := cfg.Transcribe
+	timeout := time.Duration(tc.ResolveTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	hc := &http.Client{Timeout: timeout}
+	ua := cfg.Scraper.UserAgent
+	log = log.With(zap.String("component", "transcribe.resolver"))
+	return &ResolverChain{
+		resolvers: []SourceResolver{
+			NewYtDlpResolver(tc.YtDlpPath, tc.YtDlpHosts),
+			NewHTMLPageResolver(hc, ua, log),
+			NewHLSVariantResolver(hc, ua),
+		},
+		timeout:   timeout,
+		http:      hc,
+		userAgent: ua,
+		log:       log,
+	}
+}
+
+// Resolve returns the media URL for rawURL. The result equals rawURL when no resolver applies.
+func (c *ResolverChain) Resolve(ctx context.Context, rawURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	u := rawURL
+	for _, r := range c.resolvers {
+		next, err := r.Resolve(ctx, u)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", r.Name(), err)
+		}
+		if next != u {
+			c.log.Info("source resolved", zap.String("resolver", r.Name()), zap.String("from", u), zap.String("to", next))
+			u = next
+		}
+	}
+	return u, nil
+}
+
+// IsLivePlaylist reports whether the HLS playlist at rawURL is a live one, i.e. still
+// growing: no #EXT-X-ENDLIST and not typed VOD. A master playlist is judged by the variant
+// the HLS resolver would pick. A playlist that can't be read is assumed live, which only
+// means a resumed job starts at the live edge instead of seeking.
+func (c *ResolverChain) IsLivePlaylist(ctx context.Context, rawURL string) bool {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	for range 2 { // the playlist itself, then the selected variant of a master playlist
+		body, _, err := fetchText(ctx, c.http, c.userAgent, rawURL, hlsPlaylistLimit, func(string) bool { return true })
+		if err != nil {
+			c.log.Warn("read hls playlist failed, assuming live", zap.Error(err), zap.String("url", rawURL))
+			return true
+		}
+		base, err := url.Parse(rawURL)
+		if err != nil {
+			return true
+		}
+		variant := selectHLSVariant(base, body)
+		if variant == "" {
+			return hlsPlaylistIsLive(body)
+		}
+		rawURL = variant
+	}
+	return true
+}
+
+// mediaExtensions are paths ffmpeg opens directly; the HTML scan skips them.
+var mediaExtensions = []string{
+	".m3u8", ".mpd", ".mp4", ".m4a", ".mkv", ".webm", ".mov", ".flv", ".ts",
+	".mp3", ".aac", ".ogg", ".opus", ".wav", ".flac",
+}
+
+// isHTTPURL reports whether u is an absolute http(s) URL.
+func isHTTPURL(u *url.URL) bool {
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+// hasMediaExtension reports whether the URL path ends in a known media extension.
+func hasMediaExtension(u *url.URL) bool {
+	p := strings.ToLower(u.Path)
+	for _, ext := range mediaExtensions {
+		if strings.HasSuffix(p, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchText GETs rawURL and returns up to limit bytes of the body with its content type.
+// Bodies whose content type does not pass accept are not read.
+func fetchText(ctx context.Context, hc *http.Client, userAgent, rawURL string, limit int64, accept func(contentType string) bool) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", "", fmt.Errorf("GET %s: http %d", rawURL, resp.StatusCode)
+	}
+	ct := strings.ToLower(resp.Header.Get("Content-Type"))
+	if !accept(ct) {
+		return "", ct, nil
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return "", ct, err
+	}
+	return string(b), ct, nil
+}
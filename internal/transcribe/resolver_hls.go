@@ -0,0 +1,162 @@
+package transcribe
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hlsPlaylistLimit bounds the size of a master playlist.
+const hlsPlaylistLimit = 1 << 20
+
+// HLSVariantResolver replaces an HLS master playlist with its cheapest variant that
+// carries audio. When that variant takes its audio from a separate rendition, the
+// audio-only rendition playlist is used instead. Media playlists are left as is.
+type HLSVariantResolver struct {
+	http      *http.Client
+	userAgent string
+}
+
+func NewHLSVariantResolver(hc *http.Client, userAgent string) *HLSVariantResolver {
+	return &HLSVariantResolver{http: hc, userAgent: userAgent}
+}
+
+func (r *HLSVariantResolver) Name() string { return "hls" }
+
+func (r *HLSVariantResolver) Resolve(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !isHTTPURL(u) || !strings.HasSuffix(strings.ToLower(u.Path), ".m3u8") {
+		return rawURL, nil
+	}
+	body, _, err := fetchText(ctx, r.http, r.userAgent, rawURL, hlsPlaylistLimit, func(string) bool { return true })
+	if err != nil {
+		return "", err
+	}
+	if media := selectHLSVariant(u, body); media != "" {
+		return media, nil
+	}
+	return rawURL, nil
+}
+
+// hlsVariant is one #EXT-X-STREAM-INF entry of a master playlist.
+type hlsVariant struct {
+	bandwidth int
+	codecs    string
+	audio     string // AUDIO rendition group id
+	uri       string
+}
+
+// selectHLSVariant picks the lowest-bandwidth variant with audio from a master playlist
+// and returns its absolute URL, or "" when body is not a master playlist.
+func selectHLSVariant(base *url.URL, body string) string {
+	var (
+		variants   []hlsVariant
+		renditions = map[string]string{} // audio group id -> rendition URI (default one preferred)
+		pending    *hlsVariant
+	)
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF:"):
+			attrs := parseHLSAttributes(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"))
+			bw, _ := strconv.Atoi(attrs["BANDWIDTH"])
+			pending = &hlsVariant{bandwidth: bw, codecs: strings.ToLower(attrs["CODECS"]), audio: attrs["AUDIO"]}
+		case strings.HasPrefix(line, "#EXT-X-MEDIA:"):
+			attrs := parseHLSAttributes(strings.TrimPrefix(line, "#EXT-X-MEDIA:"))
+			if attrs["TYPE"] != "AUDIO" || attrs["URI"] == "" {
+				continue
+			}
+			if _, seen := renditions[attrs["GROUP-ID"]]; !seen || attrs["DEFAULT"] == "YES" {
+				renditions[attrs["GROUP-ID"]] = attrs["URI"]
+			}
+		case line == "" || strings.HasPrefix(line, "#"):
+		default:
+			if pending != nil {
+				pending.uri = line
+				variants = append(variants, *pending)
+				pending = nil
+			}
+		}
+	}
+	if len(variants) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(variants, func(a, b int) bool { return variants[a].bandwidth < variants[b].bandwidth })
+	for _, v := range variants {
+		uri := ""
+		switch {
+		case renditions[v.audio] != "":
+			uri = renditions[v.audio]
+		case hlsHasAudio(v):
+			uri = v.uri
+		default:
+			continue
+		}
+		if ref, err := url.Parse(uri); err == nil {
+			return base.ResolveReference(ref).String()
+		}
+	}
+	return ""
+}
+
+// hlsPlaylistIsLive reports whether a media playlist is still being appended to: VOD and
+// finished event playlists are closed with #EXT-X-ENDLIST.
+func hlsPlaylistIsLive(body string) bool {
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "#EXT-X-ENDLIST" || line == "#EXT-X-PLAYLIST-TYPE:VOD" {
+			return false
+		}
+	}
+	return true
+}
+
+// hlsHasAudio reports whether the variant stream itself carries audio. Without CODECS
+// it can't be told, so the variant is assumed to be muxed.
+func hlsHasAudio(v hlsVariant) bool {
+	if v.codecs == "" {
+		return true
+	}
+	for _, c := range []string{"mp4a", "ac-3", "ec-3", "opus", "mp3", "flac"} {
+		if strings.Contains(v.codecs, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHLSAttributes parses an HLS attribute list: KEY=value,KEY="quoted, value".
+func parseHLSAttributes(s string) map[string]string {
+	attrs := map[string]string{}
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.TrimSpace(s[:eq])
+		s = s[eq+1:]
+		var val string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				val, s = s[1:], ""
+			} else {
+				val, s = s[1:end+1], s[end+2:]
+			}
+		} else if comma := strings.IndexByte(s, ','); comma >= 0 {
+			val, s = s[:comma], s[comma:]
+		} else {
+			val, s = s, ""
+		}
+		attrs[key] = val
+		s = strings.TrimPrefix(s, ",")
+	}
+	return attrs
+}
@@ -0,0 +1,98 @@
+package transcribe
+
+import (
+	"context"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// htmlPageLimit bounds how much of a page is scanned for an embedded player.
+const htmlPageLimit = 2 << 20
+
+var (
+	// <meta property="og:video" content="..."> with either attribute order;
+	// og:video:url and og:video:secure_url are accepted too.
+	ogVideoRe = regexp.MustCompile(`(?is)<meta\s[^>]*(?:property|name)\s*=\s*["']og:video(?::secure_url|:url)?["'][^>]*>`)
+	contentRe = regexp.MustCompile(`(?is)\scontent\s*=\s*["']([^"']+)["']`)
+	// src of <video> and of <source> elements inside it
+	videoSrcRe = regexp.MustCompile(`(?is)<(?:video|source)\s[^>]*\bsrc\s*=\s*["']([^"']+)["']`)
+	// any HLS playlist URL, e.g. in player configuration scripts
+	m3u8Re = regexp.MustCompile(`(?i)(?:https?:)?(?:\\?/){2}[^\s"'<>]+?\.m3u8(?:\?[^\s"'<>]*)?`)
+)
+
+// HTMLPageResolver finds the media behind an article page with an embedded player:
+// og:video metadata first, then <video>/<source> elements, then any .m3u8 link.
+// The scan is a guess: a page that can't be fetched is passed on unchanged, and the
+// job only fails if ffmpeg can't open it either.
+type HTMLPageResolver struct {
+	http      *http.Client
+	userAgent string
+	log       *zap.Logger
+}
+
+func NewHTMLPageResolver(hc *http.Client, userAgent string, log *zap.Logger) *HTMLPageResolver {
+	return &HTMLPageResolver{http: hc, userAgent: userAgent, log: log}
+}
+
+func (r *HTMLPageResolver) Name() string { return "html" }
+
+func (r *HTMLPageResolver) Resolve(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !isHTTPURL(u) || hasMediaExtension(u) {
+		return rawURL, nil
+	}
+	body, _, err := fetchText(ctx, r.http, r.userAgent, rawURL, htmlPageLimit, func(ct string) bool {
+		return strings.HasPrefix(ct, "text/html") || strings.HasPrefix(ct, "application/xhtml")
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		r.log.Warn("fetch page failed, passing url on unresolved", zap.Error(err), zap.String("url", rawURL))
+		return rawURL, nil
+	}
+	if body == "" {
+		return rawURL, nil // not a page: leave it to ffmpeg
+	}
+	if media := findPageMedia(u, body); media != "" {
+		return media, nil
+	}
+	return rawURL, nil
+}
+
+// findPageMedia returns the absolute media URL embedded in page, or "" if there is none.
+func findPageMedia(page *url.URL, body string) string {
+	var candidates []string
+	for _, tag := range ogVideoRe.FindAllString(body, -1) {
+		if m := contentRe.FindStringSubmatch(tag); m != nil {
+			candidates = append(candidates, m[1])
+		}
+	}
+	for _, m := range videoSrcRe.FindAllStringSubmatch(body, -1) {
+		candidates = append(candidates, m[1])
+	}
+	for _, m := range m3u8Re.FindAllString(body, -1) {
+		candidates = append(candidates, strings.ReplaceAll(m, `\/`, "/")) // JSON-escaped
+	}
+
+	for _, c := range candidates {
+		c = strings.TrimSpace(html.UnescapeString(c))
+		if c == "" || strings.HasPrefix(c, "blob:") || strings.HasPrefix(c, "data:") {
+			continue
+		}
+		ref, err := url.Parse(c)
+		if err != nil {
+			continue
+		}
+		abs := page.ResolveReference(ref)
+		if isHTTPURL(abs) {
+			return abs.String()
+		}
+	}
+	return ""
+}
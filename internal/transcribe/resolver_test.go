@@ -0,0 +1,86 @@
+package transcribe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSelectHLSVariant(t *testing.T) {
+	base, _ := url.Parse("https://cdn.example.com/live/master.m3u8?token=1")
+
+	muxed := `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=2500000,CODECS="avc1.64001f,mp4a.40.2",RESOLUTION=1280x720
+720p.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=300000,CODECS="avc1.42e00a"
+video-only.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=800000,CODECS="avc1.4d401e,mp4a.40.2"
+360p/index.m3u8
+`
+	assert.Equal(t, "https://cdn.example.com/live/360p/index.m3u8", selectHLSVariant(base, muxed))
+
+	separateAudio := `#EXTM3U
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",NAME="en",DEFAULT=NO,URI="audio/en.m3u8"
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",NAME="uk",DEFAULT=YES,URI="audio/uk.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=1000000,CODECS="avc1.4d401e,mp4a.40.2",AUDIO="aud"
+https://other.example.com/v.m3u8
+`
+	assert.Equal(t, "https://cdn.example.com/live/audio/uk.m3u8", selectHLSVariant(base, separateAudio))
+
+	media := "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nseg1.ts\n"
+	assert.Empty(t, selectHLSVariant(base, media))
+}
+
+func TestHLSPlaylistIsLive(t *testing.T) {
+	live := "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXT-X-MEDIA-SEQUENCE:1042\n#EXTINF:6,\nseg1042.ts\n"
+	assert.True(t, hlsPlaylistIsLive(live))
+
+	vod := "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nseg1.ts\n#EXTINF:4,\nseg2.ts\n#EXT-X-ENDLIST\n"
+	assert.False(t, hlsPlaylistIsLive(vod))
+
+	// the playlist type alone is enough
+	typed := "#EXTM3U\n#EXT-X-PLAYLIST-TYPE:VOD\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nseg1.ts\n"
+	assert.False(t, hlsPlaylistIsLive(typed))
+}
+
+func TestFindPageMedia(t *testing.T) {
+	page, _ := url.Parse("https://news.example.com/a/story.html")
+
+	og := `<html><head><meta content="https://video.example.com/clip.mp4" property="og:video:secure_url"></head></html>`
+	assert.Equal(t, "https://video.example.com/clip.mp4", findPageMedia(page, og))
+
+	video := `<video controls><source src="/media/clip.webm" type="video/webm"></video>`
+	assert.Equal(t, "https://news.example.com/media/clip.webm", findPageMedia(page, video))
+
+	script := `<script>player.setup({"file":"https:\/\/live.example.com\/ch1\/master.m3u8?x=1"})</script>`
+	assert.Equal(t, "https://live.example.com/ch1/master.m3u8?x=1", findPageMedia(page, script))
+
+	assert.Empty(t, findPageMedia(page, `<p>no player here</p>`))
+}
+
+func TestHTMLPageResolverFallsBackOnFetchError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/forbidden" {
+			http.Error(w, "bots not welcome", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<video src="/media/clip.mp4"></video>`))
+	}))
+	defer srv.Close()
+	r := NewHTMLPageResolver(srv.Client(), "", zap.NewNop())
+
+	got, err := r.Resolve(context.Background(), srv.URL+"/forbidden")
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/forbidden", got)
+
+	got, err = r.Resolve(context.Background(), srv.URL+"/story")
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/media/clip.mp4", got)
+}
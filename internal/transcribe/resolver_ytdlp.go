@@ -0,0 +1,63 @@
+package transcribe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// YtDlpResolver asks the yt-dlp executable for a direct audio (or muxed) stream URL.
+// It only runs for the configured hosts (and their subdomains), since yt-dlp is slow
+// compared to ffmpeg opening a plain media URL.
+type YtDlpResolver struct {
+	path  string
+	hosts []string
+}
+
+func NewYtDlpResolver(path string, hosts []string) *YtDlpResolver {
+	if path == "" {
+		path = "yt-dlp"
+	}
+	return &YtDlpResolver{path: path, hosts: hosts}
+}
+
+func (r *YtDlpResolver) Name() string { return "yt-dlp" }
+
+func (r *YtDlpResolver) Resolve(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !isHTTPURL(u) || !r.handles(u.Hostname()) {
+		return rawURL, nil
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.path,
+		"--no-playlist", "--no-warnings",
+		"-f", "bestaudio/best",
+		"--get-url", rawURL,
+	)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	sc := bufio.NewScanner(&stdout)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("no media url for %s", rawURL)
+}
+
+func (r *YtDlpResolver) handles(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range r.hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h != "" && (host == h || strings.HasSuffix(host, "."+h)) {
+			return true
+		}
+	}
+	return false
+}
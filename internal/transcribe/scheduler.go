@@ -0,0 +1,147 @@
+package transcribe
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"news-scrabber/internal/config"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"go.uber.org/zap"
+)
+
+// Priority is the admission class of a Whisper request; lower values are admitted first.
+type Priority int
+
+const (
+	// PriorityLive is for chunks of live sources, which fall behind the broadcast when delayed.
+	PriorityLive Priority = iota
+	// PriorityBackfill is for files and VODs that can wait.
+	PriorityBackfill
+	numPriorities
+)
+
+func (p Priority) String() string {
+	if p == PriorityLive {
+		return "live"
+	}
+	return "backfill"
+}
+
+// WhisperScheduler admits Whisper requests of all jobs in this process against a shared
+// capacity (Whisper.MaxConcurrent). Waiting requests are served by priority, then in arrival
+// order, so a backfill never delays a live chunk that is ready. Capacity is per instance:
+// the cluster-wide bound is MaxConcurrent times the number of instances.
+type WhisperScheduler struct {
+	capacity int
+	stats    statsd.ClientInterface
+	log      *zap.Logger
+
+	mu     sync.Mutex
+	inUse  int
+	queues [numPriorities][]*admission
+}
+
+// admission is one waiting request; guarded by WhisperScheduler.mu.
+type admission struct {
+	ready    chan struct{}
+	queued   time.Time
+	priority Priority
+	granted  bool
+}
+
+func NewWhisperScheduler(cfg *config.Config, stats statsd.ClientInterface, log *zap.Logger) *WhisperScheduler {
+	return &WhisperScheduler{
+		capacity: max(cfg.Whisper.MaxConcurrent, 1),
+		stats:    stats,
+		log:      log.With(zap.String("component", "transcribe.scheduler")),
+	}
+}
+
+// Acquire blocks until a slot is free for a request of the given priority and returns the
+// function releasing it. It returns ctx.Err() if ctx ends while waiting.
+func (s *WhisperScheduler) Acquire(ctx context.Context, priority Priority) (func(), error) {
+	a := &admission{ready: make(chan struct{}), queued: time.Now(), priority: priority}
+	s.mu.Lock()
+	s.queues[priority] = append(s.queues[priority], a)
+	s.dispatch()
+	s.mu.Unlock()
+
+	select {
+	case <-a.ready:
+		return s.releaseFunc(), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if a.granted {
+			// granted while we were giving up: hand the slot to the next request
+			s.inUse--
+			s.dispatch()
+		} else {
+			s.remove(a)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (s *WhisperScheduler) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.inUse--
+			s.dispatch()
+		})
+	}
+}
+
+// dispatch grants free slots to the oldest request of the highest waiting priority.
+func (s *WhisperScheduler) dispatch() {
+	for s.inUse < s.capacity {
+		a := s.pop()
+		if a == nil {
+			break
+		}
+		a.granted = true
+		s.inUse++
+		close(a.ready)
+		wait := time.Since(a.queued)
+		tags := []string{"priority:" + a.priority.String()}
+		_ = s.stats.Timing("transcribe.whisper.queue_time", wait, tags, 1)
+		if wait > time.Minute {
+			s.log.Warn("whisper request waited long for a slot", zap.Duration("wait", wait), zap.Stringer("priority", a.priority))
+		}
+	}
+	s.gauges()
+}
+
+func (s *WhisperScheduler) pop() *admission {
+	for p := range s.queues {
+		if q := s.queues[p]; len(q) > 0 {
+			s.queues[p] = q[1:]
+			return q[0]
+		}
+	}
+	return nil
+}
+
+func (s *WhisperScheduler) remove(a *admission) {
+	q := s.queues[a.priority]
+	for i, w := range q {
+		if w == a {
+			s.queues[a.priority] = append(q[:i:i], q[i+1:]...)
+			break
+		}
+	}
+	s.gauges()
+}
+
+func (s *WhisperScheduler) gauges() {
+	_ = s.stats.Gauge("transcribe.whisper.in_flight", float64(s.inUse), nil, 1)
+	for p := range s.queues {
+		tags := []string{"priority:" + Priority(p).String()}
+		_ = s.stats.Gauge("transcribe.whisper.queued", float64(len(s.queues[p])), tags, 1)
+	}
+}
@@ -0,0 +1,67 @@
+package transcribe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testScheduler(capacity int) *WhisperScheduler {
+	return &WhisperScheduler{capacity: capacity, stats: &statsd.NoOpClient{}, log: zap.NewNop()}
+}
+
+// queueWaiter starts an Acquire in the background and waits until it is queued.
+func queueWaiter(t *testing.T, s *WhisperScheduler, ctx context.Context, p Priority, order chan<- Priority) {
+	t.Helper()
+	s.mu.Lock()
+	before := len(s.queues[p])
+	s.mu.Unlock()
+	go func() {
+		release, err := s.Acquire(ctx, p)
+		if err != nil {
+			return
+		}
+		order <- p
+		release()
+	}()
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.queues[p]) > before
+	}, time.Second, time.Millisecond)
+}
+
+func TestWhisperSchedulerLiveFirst(t *testing.T) {
+	s := testScheduler(1)
+	release, err := s.Acquire(context.Background(), PriorityBackfill)
+	require.NoError(t, err)
+
+	order := make(chan Priority, 2)
+	queueWaiter(t, s, context.Background(), PriorityBackfill, order)
+	queueWaiter(t, s, context.Background(), PriorityLive, order)
+
+	release()
+	assert.Equal(t, PriorityLive, <-order)
+	assert.Equal(t, PriorityBackfill, <-order)
+}
+
+func TestWhisperSchedulerCancelledWaiter(t *testing.T) {
+	s := testScheduler(1)
+	release, err := s.Acquire(context.Background(), PriorityLive)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = s.Acquire(ctx, PriorityLive)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, s.queues[PriorityLive])
+
+	release()
+	release() // idempotent
+	assert.Equal(t, 0, s.inUse)
+}
@@ -3,8 +3,6 @@ package transcribe
 import (
 	"context"
 	"errors"
-	"fmt"
-	"time"
 
 	"go.uber.org/zap"
 )
@@ -17,6 +15,9 @@ type Service struct {
 }
 
 func NewService(p JobParams) (*Service, error) {
+	if _, err := archiveCodec(p.Cfg.Transcribe); err != nil {
+		return nil, err
+	}
 	return &Service{
 		log:  p.Log.With(zap.String("component", "transcribe")),
 		deps: p,
@@ -25,12 +26,19 @@ func NewService(p JobParams) (*Service, error) {
 
 // IngestURL runs a single ingest/transcribe job synchronously and returns when finished.
 // The dispatcher (NATS consumer) should call this under its own concurrency control.
-func (s *Service) IngestURL(ctx context.Context, url, jobID string) error {
+// Closing drain (may be nil) stops taking new audio: chunks in flight are finished and
+// checkpointed, then IngestURL returns ErrJobDrained without completing the job.
+func (s *Service) IngestURL(ctx context.Context, drain <-chan struct{}, url, jobID string, opts JobOptions) error {
 	if url == "" {
 		return errors.New("url is required")
 	}
 	if jobID == "" {
-		jobID = fmt.Sprintf("job-%d", time.Now().UnixNano())
+		jobID = newJobID(url)
+	}
+	job := NewIngestJob(s.deps, jobID, url, opts)
+	job.drain = drain
+	if err := job.resolveSource(ctx); err != nil {
+		return err
 	}
-	return NewIngestJob(s.deps, jobID, url).Start(ctx)
+	return job.Start(ctx)
 }
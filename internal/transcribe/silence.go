@@ -0,0 +1,172 @@
+package transcribe
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// silenceWindowSamples is the RMS analysis window: 20 ms at 16 kHz.
+const silenceWindowSamples = wavSampleRate / 50
+
+// silenceSegmenter cuts a raw 16 kHz mono s16le stream into WAV segments. A segment is
+// cut in the middle of the first pause found once it is at least target-tolerance long;
+// without a pause it is cut at the quietest window before target+tolerance.
+type silenceSegmenter struct {
+	dir        string
+	nextIndex  int
+	offset     float64 // stream offset (seconds) of the next segment
+	minWindows int     // earliest cut, in analysis windows
+	maxWindows int     // latest cut, in analysis windows
+	pauseRun   int     // windows below threshold that make a pause
+	threshold  float64 // RMS amplitude (0..32768) treated as silence
+
+	// onSegment is called for every written segment with its stream offset.
+	onSegment func(idx int, path string, startSeconds float64)
+}
+
+func newSilenceSegmenter(dir string, startIndex int, offset float64, targetSeconds, toleranceSeconds int, thresholdDB float64, minPauseMillis int) *silenceSegmenter {
+	windowsPerSecond := wavSampleRate / silenceWindowSamples
+	tol := toleranceSeconds
+	if tol >= targetSeconds {
+		tol = targetSeconds / 2
+	}
+	run := minPauseMillis * windowsPerSecond / 1000
+	if run < 1 {
+		run = 1
+	}
+	return &silenceSegmenter{
+		dir:        dir,
+		nextIndex:  startIndex,
+		offset:     offset,
+		minWindows: (targetSeconds - tol) * windowsPerSecond,
+		maxWindows: (targetSeconds + tol) * windowsPerSecond,
+		pauseRun:   run,
+		threshold:  32768 * math.Pow(10, thresholdDB/20),
+	}
+}
+
+// run reads PCM until EOF, writing segments and sending their paths to out.
+// The audio after the last cut is flushed as a final segment.
+func (s *silenceSegmenter) run(ctx context.Context, r io.Reader, out chan<- string) error {
+	const windowBytes = silenceWindowSamples * 2
+	var (
+		buf      []byte // audio of the current segment
+		scanned  int    // windows already analysed in buf
+		run      int    // current run of silent windows
+		quietest = -1   // window with the lowest RMS in [minWindows, maxWindows)
+		quietRMS float64
+	)
+	emit := func(cutWindow int) error {
+		cut := cutWindow * windowBytes
+		if err := s.writeSegment(ctx, buf[:cut], out); err != nil {
+			return err
+		}
+		buf = append(buf[:0], buf[cut:]...)
+		scanned, run, quietest = 0, 0, -1
+		return nil
+	}
+
+	chunk := make([]byte, 64<<10)
+	for {
+		n, rerr := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+
+		for scanned < len(buf)/windowBytes {
+			w := scanned
+			rms := windowRMS(buf[w*windowBytes : (w+1)*windowBytes])
+			scanned++
+
+			if rms < s.threshold {
+				run++
+			} else {
+				if run >= s.pauseRun && w-run/2 >= s.minWindows {
+					if err := emit(w - run/2); err != nil {
+						return err
+					}
+					continue
+				}
+				run = 0
+			}
+			if w >= s.minWindows && (quietest < 0 || rms < quietRMS) {
+				quietest, quietRMS = w, rms
+			}
+			if scanned >= s.maxWindows {
+				cut := quietest
+				if cut <= 0 {
+					cut = scanned
+				}
+				if err := emit(cut); err != nil {
+					return err
+				}
+			}
+		}
+
+		if rerr != nil {
+			if errors.Is(rerr, io.EOF) {
+				if len(buf) > 0 {
+					return s.writeSegment(ctx, buf[:len(buf)&^1], out)
+				}
+				return nil
+			}
+			return rerr
+		}
+	}
+}
+
+// writeSegment stores pcm as the next segment file. It is written under a temporary
+// name and renamed, so a segment_*.wav on disk is always complete.
+func (s *silenceSegmenter) writeSegment(ctx context.Context, pcm []byte, out chan<- string) error {
+	idx := s.nextIndex
+	path := filepath.Join(s.dir, fmt.Sprintf("segment_%05d.wav", idx))
+	tmp := path + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := writeWAVHeader(f, int64(len(pcm))); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(pcm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	start := s.offset
+	s.nextIndex++
+	s.offset += float64(len(pcm)) / wavBytesPerSecond
+	if s.onSegment != nil {
+		s.onSegment(idx, path, start)
+	}
+	select {
+	case out <- path:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func windowRMS(pcm []byte) float64 {
+	var sum float64
+	n := len(pcm) / 2
+	for i := 0; i < n; i++ {
+		v := float64(int16(binary.LittleEndian.Uint16(pcm[2*i:])))
+		sum += v * v
+	}
+	if n == 0 {
+		return 0
+	}
+	return math.Sqrt(sum / float64(n))
+}
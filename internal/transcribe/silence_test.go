@@ -0,0 +1,70 @@
+package transcribe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pcm builds 16 kHz mono s16le audio: a 440 Hz tone when loud, digital silence otherwise.
+func pcm(seconds float64, loud bool) []byte {
+	n := int(seconds * wavSampleRate)
+	b := make([]byte, 2*n)
+	for i := 0; i < n; i++ {
+		var v int16
+		if loud {
+			v = int16(8000 * math.Sin(2*math.Pi*440*float64(i)/wavSampleRate))
+		}
+		binary.LittleEndian.PutUint16(b[2*i:], uint16(v))
+	}
+	return b
+}
+
+func TestSilenceSegmenterCutsAtPause(t *testing.T) {
+	var stream []byte
+	stream = append(stream, pcm(8, true)...)
+	stream = append(stream, pcm(1, false)...) // pause centred at 8.5s
+	stream = append(stream, pcm(6, true)...)
+
+	seg := newSilenceSegmenter(t.TempDir(), 3, 120, 10, 3, -40, 300)
+	var starts []float64
+	seg.onSegment = func(idx int, _ string, start float64) { starts = append(starts, start) }
+
+	out := make(chan string, 8)
+	require.NoError(t, seg.run(t.Context(), bytes.NewReader(stream), out))
+	close(out)
+
+	var paths []string
+	for p := range out {
+		paths = append(paths, p)
+	}
+	require.Len(t, paths, 2)
+	assert.Contains(t, paths[0], "segment_00003.wav")
+	assert.Contains(t, paths[1], "segment_00004.wav")
+
+	first, err := wavSeconds(paths[0])
+	require.NoError(t, err)
+	assert.InDelta(t, 8.5, first, 0.05)
+	assert.InDelta(t, 120, starts[0], 1e-9)
+	assert.InDelta(t, 128.5, starts[1], 0.05)
+}
+
+func TestSilenceSegmenterCutsWithoutPause(t *testing.T) {
+	seg := newSilenceSegmenter(t.TempDir(), 0, 0, 10, 2, -40, 300)
+	out := make(chan string, 8)
+	require.NoError(t, seg.run(t.Context(), bytes.NewReader(pcm(25, true)), out))
+	close(out)
+
+	var total float64
+	for p := range out {
+		secs, err := wavSeconds(p)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, secs, 12.0)
+		total += secs
+	}
+	assert.InDelta(t, 25, total, 0.01)
+}
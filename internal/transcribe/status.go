@@ -0,0 +1,367 @@
+package transcribe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"news-scrabber/internal/config"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// JobState is a lifecycle state of a transcription job.
+type JobState string
+
+const (
+	JobStateQueued    JobState = "queued"
+	JobStateRunning   JobState = "running"
+	JobStateRetrying  JobState = "retrying" // a run failed; redelivered after a backoff
+	JobStateFinished  JobState = "finished"
+	JobStateFailed    JobState = "failed"
+	JobStateCancelled JobState = "cancelled"
+)
+
+// jobStatusTTL bounds how long a job record is kept after its last update.
+const jobStatusTTL = 7 * 24 * time.Hour
+
+// ErrJobNotFound is returned when no status record exists for a job.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobStatus is the persisted lifecycle record of a transcription job.
+// NOTE: Keep this backward compatible; evolve by adding new json fields.
+type JobStatus struct {
+	JobID             string     `json:"job_id"`
+	SourceURL         string     `json:"source_url"`
+	ResolvedURL       string     `json:"resolved_url,omitempty"` // media URL ffmpeg opens, when it differs from SourceURL
+	State             JobState   `json:"state"`
+	ChunksProcessed   int        `json:"chunks_processed"`
+	LastChunkIndex    int        `json:"last_chunk_index"` // -1 until the first chunk is processed
+	LastError         string     `json:"last_error,omitempty"`
+	Attempts          int        `json:"attempts,omitempty"`        // failed runs since the request was queued
+	WhisperAttempt    int        `json:"whisper_attempt,omitempty"` // attempt on which the latest retried Whisper request succeeded
+	WhisperRetries    int        `json:"whisper_retries,omitempty"` // Whisper attempts beyond the first, cumulative
+	QueuedAt          time.Time  `json:"queued_at"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+	CancelRequestedAt *time.Time `json:"cancel_requested_at,omitempty"`
+	// Unpublished marks a request that failed to reach the stream: the job was never queued.
+	Unpublished bool `json:"unpublished,omitempty"`
+}
+
+// IsTerminal reports whether the job reached a final state.
+func (s *JobStatus) IsTerminal() bool {
+	return s.State == JobStateFinished || s.State == JobStateFailed || s.State == JobStateCancelled
+}
+
+// StatusRegistry stores job lifecycle records in a NATS KV bucket. Both API instances
+// (queue, cancel) and the owning Dispatcher write a record, so every read-modify-write is
+// a compare-and-set on the key revision, retried when another writer got in between.
+type StatusRegistry struct {
+	js     jetstream.JetStream
+	bucket string
+	log    *zap.Logger
+
+	mu sync.Mutex
+	kv casStore // set on start
+}
+
+// statusUpdateAttempts bounds the compare-and-set retries of one Update.
+const statusUpdateAttempts = 8
+
+// statusTimeout bounds a single KV call of the registry.
+const statusTimeout = 5 * time.Second
+
+// errStatusConflict is returned when an Update kept losing the race to other writers.
+var errStatusConflict = errors.New("job status update conflict")
+
+func NewStatusRegistry(lc fx.Lifecycle, js jetstream.JetStream, cfg *config.Config, log *zap.Logger) *StatusRegistry {
+	r := &StatusRegistry{
+		js:     js,
+		bucket: cfg.JetStream.KVBucket + "_jobs",
+		log:    log.With(zap.String("component", "transcribe.status")),
+	}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			_, err := r.store(ctx)
+			return err
+		},
+	})
+	return r
+}
+
+// store returns the status bucket, creating it on first use. Records expire jobStatusTTL
+// after their last update.
+func (r *StatusRegistry) store(ctx context.Context) (casStore, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.kv != nil {
+		return r.kv, nil
+	}
+	kv, err := r.js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:  r.bucket,
+		TTL:     jobStatusTTL,
+		Storage: jetstream.FileStorage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("status bucket %s: %w", r.bucket, err)
+	}
+	r.kv = jsCASStore{kv: kv}
+	return r.kv, nil
+}
+
+// Get returns the status record for the job or ErrJobNotFound.
+func (r *StatusRegistry) Get(jobID string) (*JobStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+	st, _, err := r.get(ctx, jobID)
+	return st, err
+}
+
+func (r *StatusRegistry) get(ctx context.Context, jobID string) (*JobStatus, uint64, error) {
+	kv, err := r.store(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	b, rev, err := kv.Get(ctx, jobStatusKey(jobID))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return nil, 0, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	var st JobStatus
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, 0, fmt.Errorf("decode job status: %w", err)
+	}
+	return &st, rev, nil
+}
+
+// Update applies fn to the current record (or a fresh one) and stores the result. fn may run
+// more than once: on a concurrent write it is applied again to the newer record.
+func (r *StatusRegistry) Update(jobID string, fn func(st *JobStatus)) (*JobStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+	kv, err := r.store(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 0; attempt < statusUpdateAttempts; attempt++ {
+		st, rev, err := r.get(ctx, jobID)
+		if errors.Is(err, ErrJobNotFound) {
+			now := time.Now().UTC()
+			st = &JobStatus{JobID: jobID, State: JobStateQueued, LastChunkIndex: -1, QueuedAt: now}
+		} else if err != nil {
+			return nil, err
+		}
+		fn(st)
+		st.UpdatedAt = time.Now().UTC()
+
+		b, err := json.Marshal(st)
+		if err != nil {
+			return nil, err
+		}
+		if rev == 0 {
+			_, err = kv.Create(ctx, jobStatusKey(jobID), b)
+		} else {
+			_, err = kv.Update(ctx, jobStatusKey(jobID), b, rev)
+		}
+		if errors.Is(err, jetstream.ErrKeyExists) {
+			continue // another writer got in between: re-read and re-apply
+		}
+		if err != nil {
+			return nil, err
+		}
+		return st, nil
+	}
+	return nil, errStatusConflict
+}
+
+// MarkQueued records a freshly requested (or replayed) job. Publishers call it before
+// the request is published; a job that is already running keeps its state.
+func (r *StatusRegistry) MarkQueued(jobID, url string) {
+	r.update(jobID, func(st *JobStatus) {
+		st.SourceURL = url
+		if st.State == JobStateRunning {
+			return
+		}
+		st.State = JobStateQueued
+		st.Attempts = 0
+		st.LastError = ""
+		st.FinishedAt = nil
+		st.CancelRequestedAt = nil
+		st.Unpublished = false
+	})
+}
+
+// MarkPublishFailed records that the request of a job could not be published.
+func (r *StatusRegistry) MarkPublishFailed(jobID string, err error) {
+	r.update(jobID, func(st *JobStatus) {
+		now := time.Now().UTC()
+		st.State = JobStateFailed
+		st.LastError = err.Error()
+		st.FinishedAt = &now
+		st.Unpublished = true
+	})
+}
+
+// MarkRunning records that an instance picked the job up. It reports false, and keeps the
+// job cancelled, when a cancel was requested before: the caller must not run it.
+func (r *StatusRegistry) MarkRunning(jobID, url string) bool {
+	run := true
+	r.update(jobID, func(st *JobStatus) {
+		now := time.Now().UTC()
+		st.SourceURL = url
+		if st.CancelRequestedAt != nil {
+			run = false
+			if st.State != JobStateCancelled {
+				st.State = JobStateCancelled
+				st.FinishedAt = &now
+			}
+			return
+		}
+		run = true
+		st.State = JobStateRunning
+		st.StartedAt = &now
+		st.FinishedAt = nil
+	})
+	return run
+}
+
+// MarkResolved records the media URL the source URL resolved to.
+func (r *StatusRegistry) MarkResolved(jobID, mediaURL string) {
+	r.update(jobID, func(st *JobStatus) {
+		st.ResolvedURL = mediaURL
+	})
+}
+
+// MarkRequeued records that the owning instance handed the job back to the queue.
+func (r *StatusRegistry) MarkRequeued(jobID string) {
+	r.update(jobID, func(st *JobStatus) {
+		st.State = JobStateQueued
+	})
+}
+
+// ChunkProcessed records a successfully processed chunk.
+func (r *StatusRegistry) ChunkProcessed(jobID string, idx int) {
+	r.update(jobID, func(st *JobStatus) {
+		st.ChunksProcessed++
+		if idx > st.LastChunkIndex {
+			st.LastChunkIndex = idx
+		}
+	})
+}
+
+// RecordWhisperRetry counts a chunk's Whisper request that is sent again after a failure.
+func (r *StatusRegistry) RecordWhisperRetry(jobID string) {
+	r.update(jobID, func(st *JobStatus) {
+		st.WhisperRetries++
+	})
+}
+
+// RecordWhisperAttempt records the attempt on which a retried Whisper request succeeded.
+// Requests that succeed at once are not recorded, so a healthy job costs no extra write.
+func (r *StatusRegistry) RecordWhisperAttempt(jobID string, attempt int) {
+	r.update(jobID, func(st *JobStatus) {
+		st.WhisperAttempt = attempt
+	})
+}
+
+// RecordError stores the latest non-fatal error without changing the state.
+func (r *StatusRegistry) RecordError(jobID string, err error) {
+	r.update(jobID, func(st *JobStatus) {
+		st.LastError = err.Error()
+	})
+}
+
+// MarkFinished records a successful job end.
+func (r *StatusRegistry) MarkFinished(jobID string) {
+	r.update(jobID, func(st *JobStatus) {
+		now := time.Now().UTC()
+		st.State = JobStateFinished
+		st.FinishedAt = &now
+	})
+}
+
+// MarkRetrying records a failed run of a job that will be redelivered and returns how many
+// runs of the job failed so far, or 0 when the record could not be updated.
+func (r *StatusRegistry) MarkRetrying(jobID string, err error) int {
+	attempts := 0
+	r.update(jobID, func(st *JobStatus) {
+		st.State = JobStateRetrying
+		st.LastError = err.Error()
+		st.Attempts++
+		attempts = st.Attempts
+	})
+	return attempts
+}
+
+// MarkFailed records that the job was given up on: dead-lettered or rejected.
+func (r *StatusRegistry) MarkFailed(jobID string, err error) {
+	r.update(jobID, func(st *JobStatus) {
+		now := time.Now().UTC()
+		st.State = JobStateFailed
+		st.LastError = err.Error()
+		st.FinishedAt = &now
+	})
+}
+
+// RequestCancel flags the job for cancellation. A job that has not been picked up yet
+// or waiting for a retry is cancelled right away; a running one is cancelled by its owning
+// Dispatcher.
+func (r *StatusRegistry) RequestCancel(jobID string) {
+	r.update(jobID, func(st *JobStatus) {
+		now := time.Now().UTC()
+		st.CancelRequestedAt = &now
+		if st.State == JobStateQueued || st.State == JobStateRetrying {
+			st.State = JobStateCancelled
+			st.FinishedAt = &now
+		}
+	})
+}
+
+// MarkCancelled records that the job was stopped on request.
+func (r *StatusRegistry) MarkCancelled(jobID string) {
+	r.update(jobID, func(st *JobStatus) {
+		now := time.Now().UTC()
+		st.State = JobStateCancelled
+		st.FinishedAt = &now
+	})
+}
+
+// update is a best-effort Update: status tracking must never break the pipeline.
+func (r *StatusRegistry) update(jobID string, fn func(st *JobStatus)) {
+	if jobID == "" {
+		return
+	}
+	if _, err := r.Update(jobID, fn); err != nil {
+		r.log.Warn("job status update failed", zap.String("job", jobID), zap.Error(err))
+	}
+}
+
+func jobStatusKey(jobID string) string {
+	return "transcribe.job." + kvSafe(jobID)
+}
+
+// kvSafe maps an arbitrary identifier onto a single NATS KV key token.
+// Bytes outside [-_a-zA-Z0-9] (including '.') are written as "=XX" so distinct ids never collide.
+func kvSafe(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "=%02X", c)
+		}
+	}
+	return b.String()
+}
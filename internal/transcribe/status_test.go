@@ -0,0 +1,67 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// racingCASStore runs race before the first Update goes through, like another writer
+// getting in between a read and its compare-and-set.
+type racingCASStore struct {
+	*memCASStore
+	race func()
+}
+
+func (s *racingCASStore) Update(ctx context.Context, key string, value []byte, revision uint64) (uint64, error) {
+	if race := s.race; race != nil {
+		s.race = nil
+		race()
+	}
+	return s.memCASStore.Update(ctx, key, value, revision)
+}
+
+func TestStatusUpdateRetriesOnConcurrentWrite(t *testing.T) {
+	store := newMemCASStore()
+	api := &StatusRegistry{log: zap.NewNop(), kv: store}
+	racing := &racingCASStore{memCASStore: store}
+	worker := &StatusRegistry{log: zap.NewNop(), kv: racing}
+
+	api.MarkQueued("job-1", "https://example.com/v.mp4")
+
+	// the API instance requests a cancel while the worker is halfway through marking it running
+	racing.race = func() { api.RequestCancel("job-1") }
+	assert.False(t, worker.MarkRunning("job-1", "https://example.com/v.mp4"), "the concurrent cancel is seen")
+
+	got, err := api.Get("job-1")
+	require.NoError(t, err)
+	assert.Equal(t, JobStateCancelled, got.State)
+	assert.NotNil(t, got.CancelRequestedAt)
+
+	_, err = api.Get("job-2")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestFailedRunIsNotTerminal(t *testing.T) {
+	r := &StatusRegistry{log: zap.NewNop(), kv: newMemCASStore()}
+	r.MarkQueued("job-1", "https://example.com/v.mp4")
+	r.MarkRunning("job-1", "https://example.com/v.mp4")
+
+	assert.Equal(t, 1, r.MarkRetrying("job-1", errors.New("ffmpeg: exit status 1")))
+	st, err := r.Get("job-1")
+	require.NoError(t, err)
+	assert.Equal(t, JobStateRetrying, st.State)
+	assert.False(t, st.IsTerminal())
+	assert.Nil(t, st.FinishedAt)
+	assert.Equal(t, "ffmpeg: exit status 1", st.LastError)
+
+	// nobody runs it while it waits for the redelivery: a cancel ends it at once
+	r.RequestCancel("job-1")
+	st, err = r.Get("job-1")
+	require.NoError(t, err)
+	assert.Equal(t, JobStateCancelled, st.State)
+}
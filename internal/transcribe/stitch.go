@@ -0,0 +1,92 @@
+package transcribe
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"news-scrabber/internal/transcribe/whisper"
+)
+
+// trimOverlap removes the part of a transcript that belongs to audio prepended from the
+// previous chunk and shifts timings back to the chunk start. A word (or a segment without
+// word timings) is kept when its midpoint lies after the overlap boundary, so a word cut
+// by the segment boundary is attributed to the chunk that heard most of it.
+// Text is only rebuilt where words were dropped. Transcripts without segments can't be
+// aligned and are returned unchanged.
+func trimOverlap(tr *whisper.Transcript, overlap float64) *whisper.Transcript {
+	if tr == nil || overlap <= 0 || len(tr.Segments) == 0 {
+		return tr
+	}
+	after := func(start, end float64) bool { return (start+end)/2 >= overlap }
+
+	out := &whisper.Transcript{Language: tr.Language, LanguageProbability: tr.LanguageProbability}
+	trimmed := false
+	for _, seg := range tr.Segments {
+		if len(seg.Words) == 0 {
+			if !after(seg.Start, seg.End) {
+				trimmed = true
+				continue
+			}
+			seg.Start -= overlap
+			seg.End -= overlap
+			out.Segments = append(out.Segments, seg)
+			continue
+		}
+
+		var kept []whisper.Word
+		for _, w := range seg.Words {
+			if after(w.Start, w.End) {
+				w.Start -= overlap
+				w.End -= overlap
+				kept = append(kept, w)
+			}
+		}
+		if len(kept) == 0 {
+			trimmed = true
+			continue
+		}
+		if len(kept) == len(seg.Words) {
+			seg.Start -= overlap
+			seg.End -= overlap
+		} else {
+			trimmed = true
+			seg.Text = joinWords(kept)
+			seg.Start = kept[0].Start
+			seg.End = kept[len(kept)-1].End
+		}
+		seg.Words = kept
+		out.Segments = append(out.Segments, seg)
+	}
+	if !trimmed {
+		out.Text = tr.Text
+		return out
+	}
+
+	parts := make([]string, 0, len(out.Segments))
+	for _, seg := range out.Segments {
+		if seg.Text != "" {
+			parts = append(parts, seg.Text)
+		}
+	}
+	out.Text = strings.Join(parts, " ")
+	return out
+}
+
+// joinWords rebuilds text from timed words. whisper.cpp and faster-whisper words carry
+// their leading space, OpenAI words don't; a space is added where it is missing.
+func joinWords(words []whisper.Word) string {
+	var sb strings.Builder
+	for i, w := range words {
+		if i > 0 && !startsWithSpace(w.Word) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(w.Word)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func startsWithSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
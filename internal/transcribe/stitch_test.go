@@ -0,0 +1,118 @@
+package transcribe
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"news-scrabber/internal/transcribe/whisper"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimOverlapWords(t *testing.T) {
+	tr := &whisper.Transcript{
+		Text: "end of sentence. Government said today",
+		Segments: []whisper.Segment{
+			{Start: 0, End: 1.5, Text: "end of sentence.", Words: []whisper.Word{
+				{Word: " end", Start: 0, End: 0.4},
+				{Word: " of", Start: 0.4, End: 0.7},
+				{Word: " sentence.", Start: 0.7, End: 1.5},
+			}},
+			{Start: 1.8, End: 4, Text: "Government said today", Words: []whisper.Word{
+				{Word: " Government", Start: 1.8, End: 2.6}, // cut at 2.0, mostly after it
+				{Word: " said", Start: 2.6, End: 3},
+				{Word: " today", Start: 3, End: 4},
+			}},
+		},
+	}
+
+	got := trimOverlap(tr, 2)
+
+	assert.Equal(t, "Government said today", got.Text)
+	require.Len(t, got.Segments, 1)
+	assert.InDelta(t, -0.2, got.Segments[0].Start, 1e-9)
+	assert.InDelta(t, 2, got.Segments[0].End, 1e-9)
+}
+
+func TestTrimOverlapSegmentsWithoutWords(t *testing.T) {
+	tr := &whisper.Transcript{
+		Segments: []whisper.Segment{
+			{Start: 0, End: 2.5, Text: "tail of previous chunk"},
+			{Start: 2.5, End: 6, Text: "new speech"},
+		},
+	}
+
+	got := trimOverlap(tr, 3)
+
+	assert.Equal(t, "new speech", got.Text)
+	require.Len(t, got.Segments, 1)
+	assert.InDelta(t, -0.5, got.Segments[0].Start, 1e-9)
+}
+
+func TestTrimOverlapBackendWordStyles(t *testing.T) {
+	cases := map[string][]whisper.Word{
+		// whisper.cpp / faster-whisper: words carry their leading space
+		"leading spaces": {
+			{Word: " Ну", Start: 0, End: 0.8},
+			{Word: " добрий", Start: 1.2, End: 1.6},
+			{Word: " день", Start: 1.6, End: 2},
+		},
+		// OpenAI: bare words
+		"bare words": {
+			{Word: "Ну", Start: 0, End: 0.8},
+			{Word: "добрий", Start: 1.2, End: 1.6},
+			{Word: "день", Start: 1.6, End: 2},
+		},
+	}
+	for name, words := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := &whisper.Transcript{
+				Text:     "Ну, добрий день!",
+				Segments: []whisper.Segment{{Start: 0, End: 2, Text: "Ну, добрий день!", Words: words}},
+			}
+
+			got := trimOverlap(tr, 1)
+			assert.Equal(t, "добрий день", got.Text)
+			assert.Equal(t, "добрий день", got.Segments[0].Text)
+
+			// nothing dropped: the punctuated text is kept
+			got = trimOverlap(tr, 0.3)
+			assert.Equal(t, "Ну, добрий день!", got.Text)
+			require.Len(t, got.Segments, 1)
+			assert.Equal(t, "Ну, добрий день!", got.Segments[0].Text)
+			assert.InDelta(t, -0.3, got.Segments[0].Start, 1e-9)
+		})
+	}
+}
+
+func TestWriteOverlapWAV(t *testing.T) {
+	dir := t.TempDir()
+	mk := func(name string, seconds float64, fill byte) string {
+		p := filepath.Join(dir, name)
+		f, err := os.Create(p)
+		require.NoError(t, err)
+		n := int64(seconds * wavBytesPerSecond)
+		require.NoError(t, writeWAVHeader(f, n))
+		buf := make([]byte, n)
+		for i := range buf {
+			buf[i] = fill
+		}
+		_, err = f.Write(buf)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		return p
+	}
+	prev := mk("prev.wav", 1, 1)
+	cur := mk("cur.wav", 2, 2)
+	dst := filepath.Join(dir, "out.wav")
+
+	got, err := writeOverlapWAV(dst, prev, cur, 0.5)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, got, 1e-9)
+
+	secs, err := wavSeconds(dst)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.5, secs, 1e-9)
+}
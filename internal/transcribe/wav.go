@@ -0,0 +1,131 @@
+package transcribe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// All segments are produced by ffmpeg as 16 kHz mono pcm_s16le WAV.
+const (
+	wavSampleRate     = 16000
+	wavBytesPerSecond = wavSampleRate * 2
+)
+
+// wavData locates the PCM payload of a WAV file. ffmpeg may put LIST/INFO chunks
+// before "data", so the header is walked chunk by chunk instead of assuming 44 bytes.
+// A zero or oversized data length (e.g. a header not yet finalized) is clamped to the file end.
+func wavData(f *os.File) (offset, size int64, err error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	var riff [12]byte
+	if _, err := f.ReadAt(riff[:], 0); err != nil {
+		return 0, 0, fmt.Errorf("read wav header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return 0, 0, errors.New("not a RIFF/WAVE file")
+	}
+	pos := int64(12)
+	for {
+		var hdr [8]byte
+		if _, err := f.ReadAt(hdr[:], pos); err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, 0, errors.New("wav data chunk not found")
+			}
+			return 0, 0, err
+		}
+		n := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+		if string(hdr[0:4]) == "data" {
+			offset = pos + 8
+			size = info.Size() - offset
+			if n > 0 && n < size {
+				size = n
+			}
+			return offset, size, nil
+		}
+		pos += 8 + n + n%2 // chunks are word aligned
+	}
+}
+
+// wavSeconds returns the audio duration of a 16 kHz mono pcm_s16le WAV file.
+func wavSeconds(path string) (float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	_, size, err := wavData(f)
+	if err != nil {
+		return 0, err
+	}
+	return float64(size) / wavBytesPerSecond, nil
+}
+
+// writeOverlapWAV writes dst as the last overlapSeconds of prev followed by all of cur.
+// It returns the overlap actually prepended, which is shorter when prev is shorter.
+func writeOverlapWAV(dst, prev, cur string, overlapSeconds float64) (float64, error) {
+	pf, err := os.Open(prev)
+	if err != nil {
+		return 0, err
+	}
+	defer pf.Close()
+	cf, err := os.Open(cur)
+	if err != nil {
+		return 0, err
+	}
+	defer cf.Close()
+
+	pOff, pSize, err := wavData(pf)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", prev, err)
+	}
+	cOff, cSize, err := wavData(cf)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", cur, err)
+	}
+
+	tail := int64(overlapSeconds*wavBytesPerSecond) &^ 1 // whole 16-bit samples
+	if tail > pSize {
+		tail = pSize &^ 1
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+	if err := writeWAVHeader(out, tail+cSize); err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(out, io.NewSectionReader(pf, pOff+pSize-tail, tail)); err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(out, io.NewSectionReader(cf, cOff, cSize)); err != nil {
+		return 0, err
+	}
+	return float64(tail) / wavBytesPerSecond, out.Close()
+}
+
+// writeWAVHeader writes a canonical 44-byte header for 16 kHz mono pcm_s16le.
+func writeWAVHeader(w io.Writer, dataSize int64) error {
+	h := make([]byte, 44)
+	copy(h[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(h[4:8], uint32(36+dataSize))
+	copy(h[8:12], "WAVE")
+	copy(h[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(h[16:20], 16) // fmt chunk size
+	binary.LittleEndian.PutUint16(h[20:22], 1)  // PCM
+	binary.LittleEndian.PutUint16(h[22:24], 1)  // mono
+	binary.LittleEndian.PutUint32(h[24:28], wavSampleRate)
+	binary.LittleEndian.PutUint32(h[28:32], wavBytesPerSecond)
+	binary.LittleEndian.PutUint16(h[32:34], 2)  // block align
+	binary.LittleEndian.PutUint16(h[34:36], 16) // bits per sample
+	copy(h[36:40], "data")
+	binary.LittleEndian.PutUint32(h[40:44], uint32(dataSize))
+	_, err := w.Write(h)
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -53,12 +54,83 @@ func (c *Client) Health(ctx context.Context) error {
 	return fmt.Errorf("whisper health failed: status=%d", resp.StatusCode)
 }
 
-// TranscribeFile sends a local audio file to the whisper server and returns plain text.
+// Transcript is a structured transcription result.
+// Segment and word times are relative to the start of the transcribed file.
+type Transcript struct {
+	Text     string    `json:"text"`
+	Segments []Segment `json:"segments,omitempty"`
+	// Language is the spoken language (ISO code): detected, or the pinned one echoed back.
+	Language string `json:"language,omitempty"`
+	// LanguageProbability is the detection confidence; 0 when the language was pinned.
+	LanguageProbability float64 `json:"language_probability,omitempty"`
+}
+
+// Segment is a timed piece of a transcript as produced by Whisper.
+type Segment struct {
+	Start        float64 `json:"start"`
+	End          float64 `json:"end"`
+	Text         string  `json:"text"`
+	AvgLogprob   float64 `json:"avg_logprob"`
+	NoSpeechProb float64 `json:"no_speech_prob"`
+	Words        []Word  `json:"words,omitempty"`
+}
+
+// Word is a timed word; only present when the server computed word timestamps.
+type Word struct {
+	Word        string  `json:"word"`
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Probability float64 `json:"probability"`
+}
+
+// Options are per-request decoding settings. Empty fields fall back to the client/server defaults.
+type Options struct {
+	Model    string
+	Language string // ISO code; "auto" or empty lets Whisper detect it
+	BeamSize int
+}
+
+// Name identifies the REST backend.
+func (c *Client) Name() string {
+	return BackendREST
+}
+
+// TranscribeFile sends a local audio file to the whisper server and returns a structured transcript.
 // This attempts linuxserver/faster-whisper compatible REST: POST /inference with multipart field "audio_file" and optional "model".
-func (c *Client) TranscribeFile(ctx context.Context, path string) (string, error) {
+func (c *Client) TranscribeFile(ctx context.Context, path string, opts Options) (*Transcript, error) {
+	model := opts.Model
+	if model == "" {
+		model = c.Model
+	}
+	fields := []formField{{"model", model}}
+	if opts.Language != "" {
+		fields = append(fields, formField{"language", opts.Language})
+	}
+	if opts.BeamSize > 0 {
+		fields = append(fields, formField{"beam_size", strconv.Itoa(opts.BeamSize)})
+	}
+	// ask for word timings; servers that don't know the field ignore it
+	fields = append(fields, formField{"word_timestamps", "true"})
+
+	url := strings.TrimRight(c.BaseURL, "/") + "/inference"
+	b, err := postMultipart(ctx, c.HTTP, url, nil, "audio_file", path, fields)
+	if err != nil {
+		return nil, err
+	}
+	return parseTranscript(b), nil
+}
+
+// formField is a plain multipart field; a slice keeps repeated names (e.g. "x[]") and order.
+type formField struct {
+	name, value string
+}
+
+// postMultipart streams path as fileField plus fields to url and returns the 2xx response body.
+// Other statuses are returned as *HTTPError.
+func postMultipart(ctx context.Context, hc *http.Client, url string, header http.Header, fileField, path string, fields []formField) ([]byte, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer f.Close()
 
@@ -71,7 +143,7 @@ func (c *Client) TranscribeFile(ctx context.Context, path string) (string, error
 		defer pw.Close()
 		defer mw.Close()
 		// file part
-		fw, err := mw.CreateFormFile("audio_file", filepath.Base(path))
+		fw, err := mw.CreateFormFile(fileField, filepath.Base(path))
 		if err != nil {
 			done <- err
 			return
@@ -80,52 +152,57 @@ func (c *Client) TranscribeFile(ctx context.Context, path string) (string, error
 			done <- err
 			return
 		}
-		// model (optional)
-		_ = mw.WriteField("model", c.Model)
+		for _, fld := range fields {
+			_ = mw.WriteField(fld.name, fld.value)
+		}
 		done <- nil
 	}()
 
-	url := strings.TrimRight(c.BaseURL, "/") + "/inference"
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, pr)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
 	}
 	req.Header.Set("Content-Type", mw.FormDataContentType())
-	resp, err := c.HTTP.Do(req)
+	resp, err := hc.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 	if derr := <-done; derr != nil {
-		return "", derr
+		return nil, derr
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		b, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("whisper http %d: %s", resp.StatusCode, string(b))
+		return nil, newHTTPError(resp, b)
 	}
-	// best-effort parse JSON {"text":"..."} or {"segments":[{"text":"..."},...]}
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+	return io.ReadAll(resp.Body)
+}
+
+// parseTranscript best-effort parses JSON {"text":"...","segments":[...]}; when "text" is
+// empty it is rebuilt from the segments, and a non-JSON body is returned as plain text.
+func parseTranscript(b []byte) *Transcript {
+	var t Transcript
+	if err := json.Unmarshal(b, &t); err != nil {
+		return &Transcript{Text: strings.TrimSpace(string(b))}
 	}
-	var jmap map[string]any
-	if err := json.Unmarshal(b, &jmap); err == nil {
-		if t, ok := jmap["text"].(string); ok && t != "" {
-			return t, nil
-		}
-		if segs, ok := jmap["segments"].([]any); ok {
-			var sb strings.Builder
-			for _, s := range segs {
-				if m, ok := s.(map[string]any); ok {
-					if t, ok := m["text"].(string); ok {
-						sb.WriteString(t)
-						sb.WriteByte(' ')
-					}
-				}
-			}
-			return strings.TrimSpace(sb.String()), nil
+	normalizeTranscript(&t)
+	return &t
+}
+
+// normalizeTranscript trims segment and full texts and rebuilds an empty text from the segments.
+func normalizeTranscript(t *Transcript) {
+	for i := range t.Segments {
+		t.Segments[i].Text = strings.TrimSpace(t.Segments[i].Text)
+	}
+	t.Text = strings.TrimSpace(t.Text)
+	if t.Text == "" && len(t.Segments) > 0 {
+		parts := make([]string, 0, len(t.Segments))
+		for _, s := range t.Segments {
+			parts = append(parts, s.Text)
 		}
+		t.Text = strings.TrimSpace(strings.Join(parts, " "))
 	}
-	// fallback: return raw body as string
-	return strings.TrimSpace(string(b)), nil
 }
@@ -0,0 +1,56 @@
+package whisper
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-scrabber/internal/config"
+)
+
+// CppClient talks to the whisper.cpp example server (POST /inference with multipart field "file").
+// The server loads a single model at startup, so per-job model names are ignored.
+type CppClient struct {
+	HTTP    *http.Client
+	BaseURL string
+}
+
+func NewCppClient(cfg *config.Config) (*CppClient, error) {
+	to := 60 * time.Second
+	if cfg.Whisper.TimeoutSeconds > 0 {
+		to = time.Duration(cfg.Whisper.TimeoutSeconds) * time.Second
+	}
+	return &CppClient{
+		HTTP:    &http.Client{Timeout: to},
+		BaseURL: cfg.Whisper.CppURL,
+	}, nil
+}
+
+// Name identifies the whisper.cpp backend.
+func (c *CppClient) Name() string {
+	return BackendWhisperCpp
+}
+
+// TranscribeFile uploads the file and asks for verbose_json, which mirrors the OpenAI layout.
+func (c *CppClient) TranscribeFile(ctx context.Context, path string, opts Options) (*Transcript, error) {
+	lang := opts.Language
+	if lang == "" {
+		lang = "auto"
+	}
+	fields := []formField{
+		{"response_format", "verbose_json"},
+		{"language", lang},
+		{"temperature", "0.0"},
+	}
+	if opts.BeamSize > 0 {
+		fields = append(fields, formField{"beam_size", strconv.Itoa(opts.BeamSize)})
+	}
+	url := strings.TrimRight(c.BaseURL, "/") + "/inference"
+	b, err := postMultipart(ctx, c.HTTP, url, nil, "file", path, fields)
+	if err != nil {
+		return nil, err
+	}
+	return parseVerboseTranscript(b), nil
+}
@@ -0,0 +1,71 @@
+package whisper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPError is a non-2xx response from a Whisper backend.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+	// RetryAfter is the server's Retry-After hint; 0 when absent.
+	RetryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("whisper http %d: %s", e.StatusCode, e.Body)
+}
+
+// Temporary reports whether the same request may succeed later: timeouts, throttling and
+// server-side failures. Other 4xx responses mean the request itself is wrong.
+func (e *HTTPError) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return e.StatusCode >= 500
+}
+
+// RetryAfter returns the Retry-After hint carried by err, or 0.
+func RetryAfter(err error) time.Duration {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		return he.RetryAfter
+	}
+	return 0
+}
+
+// maxErrorBody bounds the response body kept in an HTTPError.
+const maxErrorBody = 512
+
+func newHTTPError(resp *http.Response, body []byte) *HTTPError {
+	b := strings.TrimSpace(string(body))
+	if len(b) > maxErrorBody {
+		b = b[:maxErrorBody] + "..."
+	}
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Body:       b,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// parseRetryAfter reads delay-seconds or an HTTP date; past dates and garbage yield 0.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
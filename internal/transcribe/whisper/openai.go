@@ -0,0 +1,65 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"news-scrabber/internal/config"
+)
+
+// OpenAIClient transcribes through the OpenAI audio API (POST {BaseURL}/audio/transcriptions),
+// or any server compatible with it. The model is fixed by OpenAIConfig; per-job Whisper model
+// names do not apply to this backend and neither does the beam size.
+type OpenAIClient struct {
+	HTTP    *http.Client
+	BaseURL string
+	APIKey  string
+	Model   string
+}
+
+func NewOpenAIClient(cfg *config.Config) (*OpenAIClient, error) {
+	if cfg.OpenAI.BaseURL == "" {
+		return nil, errors.New("openai whisper backend requires OPENAI_BASE_URL")
+	}
+	to := 120 * time.Second
+	if cfg.OpenAI.TimeoutSec > 0 {
+		to = time.Duration(cfg.OpenAI.TimeoutSec) * time.Second
+	}
+	return &OpenAIClient{
+		HTTP:    &http.Client{Timeout: to},
+		BaseURL: cfg.OpenAI.BaseURL,
+		APIKey:  cfg.OpenAI.APIKey,
+		Model:   cfg.OpenAI.Model,
+	}, nil
+}
+
+// Name identifies the OpenAI backend.
+func (c *OpenAIClient) Name() string {
+	return BackendOpenAI
+}
+
+// TranscribeFile uploads the file and asks for verbose_json with segment and word timings.
+func (c *OpenAIClient) TranscribeFile(ctx context.Context, path string, opts Options) (*Transcript, error) {
+	fields := []formField{
+		{"model", c.Model},
+		{"response_format", "verbose_json"},
+		{"timestamp_granularities[]", "segment"},
+		{"timestamp_granularities[]", "word"},
+	}
+	if opts.Language != "" && opts.Language != "auto" {
+		fields = append(fields, formField{"language", opts.Language})
+	}
+	header := http.Header{}
+	if c.APIKey != "" {
+		header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	url := strings.TrimRight(c.BaseURL, "/") + "/audio/transcriptions"
+	b, err := postMultipart(ctx, c.HTTP, url, header, "file", path, fields)
+	if err != nil {
+		return nil, err
+	}
+	return parseVerboseTranscript(b), nil
+}
@@ -0,0 +1,207 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"news-scrabber/internal/config"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// ErrNoEndpoint is returned when every REST endpoint is unhealthy or has its breaker open.
+var ErrNoEndpoint = errors.New("no healthy whisper endpoint")
+
+// healthProbeTimeout bounds a single Client.Health call.
+const healthProbeTimeout = 5 * time.Second
+
+// Pool balances the REST backend across several Whisper servers. Each chunk goes to the
+// healthy endpoint with the fewest requests in flight. Endpoints are probed with Client.Health,
+// and a circuit breaker takes one out of rotation after repeated failed requests, so a hung
+// server stops attracting new chunks. After the cooldown a single trial request is let through.
+type Pool struct {
+	endpoints []*endpoint
+	interval  time.Duration
+	threshold int
+	cooldown  time.Duration
+	log       *zap.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// endpoint is the routing state of one server; guarded by Pool.mu.
+type endpoint struct {
+	client    *Client
+	inFlight  int
+	healthy   bool
+	failures  int       // consecutive failed requests
+	openUntil time.Time // breaker open until then; zero when closed
+	trial     bool      // a half-open trial request is in flight
+}
+
+func NewPool(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger) (*Pool, error) {
+	wc := cfg.Whisper
+	urls := wc.URLs
+	if len(urls) == 0 {
+		urls = []string{wc.URL}
+	}
+	p := &Pool{
+		interval:  time.Duration(wc.HealthCheckIntervalSeconds) * time.Second,
+		threshold: max(wc.BreakerFailures, 1),
+		cooldown:  time.Duration(wc.BreakerCooldownSeconds) * time.Second,
+		log:       log.With(zap.String("component", "transcribe.whisper.pool")),
+	}
+	if p.interval <= 0 {
+		p.interval = 15 * time.Second
+	}
+	if p.cooldown <= 0 {
+		p.cooldown = 30 * time.Second
+	}
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		c, err := NewClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		c.BaseURL = u
+		p.endpoints = append(p.endpoints, &endpoint{client: c, healthy: true})
+	}
+	if len(p.endpoints) == 0 {
+		return nil, errors.New("no whisper endpoint configured")
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			p.cancel = cancel
+			go p.probeLoop(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			if p.cancel != nil {
+				p.cancel()
+			}
+			return nil
+		},
+	})
+	return p, nil
+}
+
+// Name identifies the REST backend.
+func (p *Pool) Name() string {
+	return BackendREST
+}
+
+func (p *Pool) TranscribeFile(ctx context.Context, path string, opts Options) (*Transcript, error) {
+	ep := p.acquire()
+	if ep == nil {
+		return nil, ErrNoEndpoint
+	}
+	tr, err := ep.client.TranscribeFile(ctx, path, opts)
+	p.release(ep, err)
+	return tr, err
+}
+
+// acquire picks the least-loaded routable endpoint and counts the request against it.
+func (p *Pool) acquire() *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	var best *endpoint
+	for _, ep := range p.endpoints {
+		if !ep.healthy || ep.trial || now.Before(ep.openUntil) {
+			continue
+		}
+		if best == nil || ep.inFlight < best.inFlight {
+			best = ep
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	if !best.openUntil.IsZero() {
+		best.trial = true // cooldown over: half-open, only this request until it settles
+	}
+	best.inFlight++
+	return best
+}
+
+// release records the outcome of a request. A caller cancelling its own request or a request
+// the server rejected as invalid says nothing about the endpoint; a timeout does, since that
+// is how a hung server shows up.
+func (p *Pool) release(ep *endpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ep.inFlight--
+	trial := ep.trial
+	ep.trial = false
+	switch {
+	case err == nil:
+		if !ep.openUntil.IsZero() {
+			p.log.Info("whisper endpoint recovered", zap.String("url", ep.client.BaseURL))
+		}
+		ep.failures = 0
+		ep.openUntil = time.Time{}
+	case errors.Is(err, context.Canceled), isRequestError(err):
+	default:
+		ep.failures++
+		if trial || ep.failures >= p.threshold {
+			ep.openUntil = time.Now().Add(p.cooldown)
+			p.log.Warn("whisper endpoint breaker open", zap.String("url", ep.client.BaseURL),
+				zap.Int("failures", ep.failures), zap.Duration("cooldown", p.cooldown), zap.Error(err))
+		}
+	}
+}
+
+func (p *Pool) probeLoop(ctx context.Context) {
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			p.probe(ctx)
+		}
+	}
+}
+
+// probe runs Client.Health against every endpoint concurrently and updates their health.
+func (p *Pool) probe(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, ep := range p.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			pctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+			err := ep.client.Health(pctx)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if healthy := err == nil; healthy != ep.healthy {
+				ep.healthy = healthy
+				if healthy {
+					p.log.Info("whisper endpoint healthy", zap.String("url", ep.client.BaseURL))
+				} else {
+					p.log.Warn("whisper endpoint unhealthy", zap.String("url", ep.client.BaseURL), zap.Error(err))
+				}
+			}
+		}(ep)
+	}
+	wg.Wait()
+}
+
+// isRequestError reports a 4xx response that is about the request, not the endpoint's health.
+func isRequestError(err error) bool {
+	var he *HTTPError
+	return errors.As(err, &he) && he.StatusCode < 500 && !he.Temporary()
+}
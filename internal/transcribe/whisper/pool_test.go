@@ -0,0 +1,74 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testPool(n int) *Pool {
+	p := &Pool{threshold: 2, cooldown: time.Minute, log: zap.NewNop()}
+	for i := 0; i < n; i++ {
+		p.endpoints = append(p.endpoints, &endpoint{client: &Client{BaseURL: string(rune('a' + i))}, healthy: true})
+	}
+	return p
+}
+
+func TestPoolRoutesToLeastLoaded(t *testing.T) {
+	p := testPool(2)
+	first := p.acquire()
+	second := p.acquire()
+	require.NotNil(t, first)
+	require.NotNil(t, second)
+	assert.NotSame(t, first, second)
+
+	p.release(first, nil)
+	assert.Same(t, first, p.acquire())
+}
+
+func TestPoolSkipsUnhealthy(t *testing.T) {
+	p := testPool(2)
+	p.endpoints[0].healthy = false
+	assert.Same(t, p.endpoints[1], p.acquire())
+	assert.Same(t, p.endpoints[1], p.acquire())
+
+	p.endpoints[1].healthy = false
+	assert.Nil(t, p.acquire())
+}
+
+func TestPoolBreaker(t *testing.T) {
+	p := testPool(1)
+	ep := p.endpoints[0]
+	hung := context.DeadlineExceeded
+
+	p.release(p.acquire(), hung)
+	require.NotNil(t, p.acquire(), "one failure stays under the threshold")
+	p.release(ep, hung)
+	assert.Nil(t, p.acquire(), "breaker opens at the threshold")
+
+	// caller cancellations do not count against the endpoint
+	ep.openUntil, ep.failures = time.Time{}, 0
+	p.release(p.acquire(), context.Canceled)
+	p.release(p.acquire(), context.Canceled)
+	assert.Equal(t, 0, ep.failures)
+
+	// after the cooldown a single trial goes through; its failure reopens the breaker
+	ep.failures = 2
+	ep.openUntil = time.Now().Add(-time.Second)
+	trial := p.acquire()
+	require.NotNil(t, trial)
+	assert.Nil(t, p.acquire(), "only one trial while half-open")
+	p.release(trial, errors.New("boom"))
+	assert.Nil(t, p.acquire())
+
+	// a successful trial closes it
+	ep.openUntil = time.Now().Add(-time.Second)
+	p.release(p.acquire(), nil)
+	assert.True(t, ep.openUntil.IsZero())
+	assert.NotNil(t, p.acquire())
+}
@@ -0,0 +1,101 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"news-scrabber/internal/config"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Transcriber turns a local audio file into a structured transcript.
+// Implementations: Pool of Clients (REST /inference servers), OpenAIClient and CppClient (whisper.cpp).
+type Transcriber interface {
+	// Name identifies the backend in logs and config (WHISPER_BACKENDS).
+	Name() string
+	TranscribeFile(ctx context.Context, path string, opts Options) (*Transcript, error)
+}
+
+// Backend names accepted in WhisperConfig.Backends.
+const (
+	BackendREST       = "rest"
+	BackendOpenAI     = "openai"
+	BackendWhisperCpp = "whispercpp"
+)
+
+// Chain tries its backends in order and returns the first transcript; a backend
+// failing makes the next one take the file. Cancellation of ctx stops the chain.
+type Chain struct {
+	backends []Transcriber
+	log      *zap.Logger
+}
+
+// NewTranscriber builds the configured backend chain. A single backend is returned as is.
+func NewTranscriber(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger) (Transcriber, error) {
+	names := cfg.Whisper.Backends
+	if len(names) == 0 {
+		names = []string{BackendREST}
+	}
+	var backends []Transcriber
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		t, err := newBackend(lc, cfg, log, name)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, t)
+	}
+	if len(backends) == 0 {
+		return nil, errors.New("no whisper backend configured")
+	}
+	if len(backends) == 1 {
+		return backends[0], nil
+	}
+	return &Chain{backends: backends, log: log.With(zap.String("component", "transcribe.whisper"))}, nil
+}
+
+func newBackend(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger, name string) (Transcriber, error) {
+	switch name {
+	case BackendREST:
+		return NewPool(lc, cfg, log)
+	case BackendOpenAI:
+		return NewOpenAIClient(cfg)
+	case BackendWhisperCpp:
+		return NewCppClient(cfg)
+	default:
+		return nil, fmt.Errorf("unknown whisper backend %q", name)
+	}
+}
+
+// Name reports the primary backend.
+func (c *Chain) Name() string {
+	return c.backends[0].Name()
+}
+
+func (c *Chain) TranscribeFile(ctx context.Context, path string, opts Options) (*Transcript, error) {
+	var errs []error
+	for i, b := range c.backends {
+		tr, err := b.TranscribeFile(ctx, path, opts)
+		if err == nil {
+			return tr, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", b.Name(), err))
+		if i+1 < len(c.backends) {
+			c.log.Warn("whisper backend failed, falling back",
+				zap.String("backend", b.Name()), zap.String("next", c.backends[i+1].Name()), zap.Error(err))
+		}
+	}
+	return nil, errors.Join(errs...)
+}
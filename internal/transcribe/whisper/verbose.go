@@ -0,0 +1,74 @@
+package whisper
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// verboseTranscript is the OpenAI verbose_json layout, also produced by whisper.cpp.
+// OpenAI returns words next to the segments; whisper.cpp may nest them per segment.
+type verboseTranscript struct {
+	Text                        string    `json:"text"`
+	Language                    string    `json:"language"`
+	LanguageProbability         float64   `json:"language_probability"`
+	DetectedLanguageProbability float64   `json:"detected_language_probability"`
+	Segments                    []Segment `json:"segments"`
+	Words                       []Word    `json:"words"`
+}
+
+// parseVerboseTranscript maps a verbose_json body onto Transcript: language names become ISO
+// codes and top-level words are attached to the segment they start in.
+func parseVerboseTranscript(b []byte) *Transcript {
+	var v verboseTranscript
+	if err := json.Unmarshal(b, &v); err != nil {
+		return parseTranscript(b)
+	}
+	attachWords(v.Segments, v.Words)
+	t := &Transcript{
+		Text:                v.Text,
+		Segments:            v.Segments,
+		Language:            languageCode(v.Language),
+		LanguageProbability: v.LanguageProbability,
+	}
+	if t.LanguageProbability == 0 {
+		t.LanguageProbability = v.DetectedLanguageProbability
+	}
+	normalizeTranscript(t)
+	return t
+}
+
+// attachWords appends each word to the segment containing its start; segments that already
+// carry words keep them.
+func attachWords(segs []Segment, words []Word) {
+	if len(segs) == 0 {
+		return
+	}
+	i := 0
+	for _, w := range words {
+		for i+1 < len(segs) && w.Start >= segs[i+1].Start {
+			i++
+		}
+		segs[i].Words = append(segs[i].Words, w)
+	}
+}
+
+// languageCodes maps the language names used in verbose_json onto ISO 639-1 codes.
+var languageCodes = map[string]string{
+	"english": "en", "ukrainian": "uk", "russian": "ru", "polish": "pl", "german": "de",
+	"french": "fr", "spanish": "es", "italian": "it", "portuguese": "pt", "dutch": "nl",
+	"czech": "cs", "slovak": "sk", "romanian": "ro", "hungarian": "hu", "bulgarian": "bg",
+	"belarusian": "be", "lithuanian": "lt", "latvian": "lv", "estonian": "et", "finnish": "fi",
+	"swedish": "sv", "norwegian": "no", "danish": "da", "greek": "el", "turkish": "tr",
+	"arabic": "ar", "hebrew": "he", "persian": "fa", "hindi": "hi", "chinese": "zh",
+	"japanese": "ja", "korean": "ko", "georgian": "ka", "armenian": "hy", "azerbaijani": "az",
+	"kazakh": "kk", "serbian": "sr", "croatian": "hr", "slovenian": "sl", "bosnian": "bs",
+}
+
+// languageCode returns an ISO code for a language name; codes and unknown names pass through lowercased.
+func languageCode(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if code, ok := languageCodes[lang]; ok {
+		return code
+	}
+	return lang
+}
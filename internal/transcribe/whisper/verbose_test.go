@@ -0,0 +1,40 @@
+package whisper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVerboseTranscript(t *testing.T) {
+	body := []byte(`{
+		"text": "",
+		"language": "Ukrainian",
+		"segments": [
+			{"start": 0, "end": 2.0, "text": " Добрий день. "},
+			{"start": 2.0, "end": 4.5, "text": " Новини. "}
+		],
+		"words": [
+			{"word": "Добрий", "start": 0.1, "end": 0.6},
+			{"word": "день", "start": 0.7, "end": 1.1},
+			{"word": "Новини", "start": 2.2, "end": 2.9}
+		]
+	}`)
+	tr := parseVerboseTranscript(body)
+	require.Len(t, tr.Segments, 2)
+	assert.Equal(t, "uk", tr.Language)
+	assert.Equal(t, "Добрий день. Новини.", tr.Text)
+	assert.Len(t, tr.Segments[0].Words, 2)
+	assert.Len(t, tr.Segments[1].Words, 1)
+}
+
+func TestParseVerboseTranscriptWhisperCpp(t *testing.T) {
+	body := []byte(`{"text": " hello", "language": "en", "detected_language_probability": 0.93,
+		"segments": [{"start": 0, "end": 1, "text": " hello", "words": [{"word": "hello", "start": 0, "end": 0.8, "probability": 0.9}]}]}`)
+	tr := parseVerboseTranscript(body)
+	assert.Equal(t, "en", tr.Language)
+	assert.InDelta(t, 0.93, tr.LanguageProbability, 1e-9)
+	assert.Equal(t, "hello", tr.Text)
+	assert.Len(t, tr.Segments[0].Words, 1)
+}
@@ -0,0 +1,131 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+
+	"news-scrabber/internal/config"
+	"news-scrabber/internal/transcribe/whisper"
+
+	"go.uber.org/zap"
+)
+
+// whisperRetryPolicy is the Whisper.Retry* configuration of a chunk's Whisper request.
+type whisperRetryPolicy struct {
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	jitter     float64
+	maxElapsed time.Duration // 0 = no limit
+}
+
+func newWhisperRetryPolicy(wc config.WhisperConfig) whisperRetryPolicy {
+	p := whisperRetryPolicy{
+		attempts:   max(wc.RetryAttempts, 1),
+		backoff:    time.Duration(wc.RetryBackoffSeconds) * time.Second,
+		maxBackoff: time.Duration(wc.RetryMaxBackoffSeconds) * time.Second,
+		jitter:     min(max(wc.RetryJitter, 0), 1),
+		maxElapsed: time.Duration(max(wc.RetryMaxElapsedSeconds, 0)) * time.Second,
+	}
+	if p.backoff <= 0 {
+		p.backoff = 2 * time.Second
+	}
+	if p.maxBackoff < p.backoff {
+		p.maxBackoff = p.backoff
+	}
+	return p
+}
+
+// delay is the wait after a failed attempt (1-based): exponential, capped, randomized by
+// ±jitter, and never shorter than the server's Retry-After. r is a uniform sample in [0,1).
+func (p whisperRetryPolicy) delay(attempt int, retryAfter time.Duration, r float64) time.Duration {
+	d := p.backoff
+	for i := 1; i < attempt && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.maxBackoff)
+	d = time.Duration(float64(d) * (1 + p.jitter*(2*r-1)))
+	return max(d, retryAfter)
+}
+
+// transcribeWithRetry wraps the whisper call with the configured retry policy for transient
+// failures: timeouts, dropped connections, throttling and 5xx responses. Cancellation of ctx
+// ends it at once. Each attempt first waits for a WhisperScheduler slot; the per-request
+// timeout (Whisper.TimeoutSeconds) only starts once the request is admitted.
+func (j *IngestJob) transcribeWithRetry(ctx context.Context, path string) (*whisper.Transcript, error) {
+	policy := newWhisperRetryPolicy(j.cfg.Whisper)
+	to := time.Duration(j.cfg.Whisper.TimeoutSeconds) * time.Second
+	if to <= 0 {
+		to = 600 * time.Second
+	}
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		release, err := j.scheduler.Acquire(ctx, j.priority())
+		if err != nil {
+			return nil, err
+		}
+		cctx, cancel := context.WithTimeout(ctx, to)
+		tr, err := j.wh.TranscribeFile(cctx, path, j.opts.whisperOptions())
+		cancel()
+		release()
+		if err == nil {
+			if attempt > 1 {
+				j.log.Info("whisper request succeeded after retries", zap.Int("attempt", attempt), zap.String("file", path))
+				j.status.RecordWhisperAttempt(j.jobID, attempt)
+			}
+			return tr, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if attempt >= policy.attempts || !isRetryableWhisperErr(err) {
+			return nil, err
+		}
+		wait := policy.delay(attempt, whisper.RetryAfter(err), rand.Float64())
+		if policy.maxElapsed > 0 && time.Since(start)+wait > policy.maxElapsed {
+			j.log.Warn("whisper retry budget exhausted", zap.Error(err), zap.Int("attempt", attempt),
+				zap.Duration("elapsed", time.Since(start)), zap.String("file", path))
+			return nil, err
+		}
+		j.log.Warn("whisper transient error, retrying", zap.Error(err), zap.Int("attempt", attempt),
+			zap.Duration("wait", wait), zap.String("file", path))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		j.status.RecordWhisperRetry(j.jobID)
+	}
+}
+
+// isRetryableWhisperErr reports whether a failed Whisper request may succeed when sent again.
+// A cancelled request is not retried; a timed out one is, since that is how an overloaded or
+// hung server shows up. Of the other network errors only a failed dial and a connection the
+// server dropped count; other errors after the request went out are not retried.
+func isRetryableWhisperErr(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, whisper.ErrNoEndpoint) {
+		return true
+	}
+	var he *whisper.HTTPError
+	if errors.As(err, &he) {
+		return he.Temporary()
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	var oe *net.OpError
+	if errors.As(err, &oe) && oe.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,52 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"news-scrabber/internal/config"
+	"news-scrabber/internal/transcribe/whisper"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhisperRetryDelay(t *testing.T) {
+	p := newWhisperRetryPolicy(config.WhisperConfig{RetryBackoffSeconds: 2, RetryMaxBackoffSeconds: 10, RetryJitter: 0.5})
+
+	// r = 0.5 is the jitter midpoint
+	assert.Equal(t, 2*time.Second, p.delay(1, 0, 0.5))
+	assert.Equal(t, 4*time.Second, p.delay(2, 0, 0.5))
+	assert.Equal(t, 10*time.Second, p.delay(5, 0, 0.5))
+	assert.Equal(t, 10*time.Second, p.delay(60, 0, 0.5), "no overflow on large attempts")
+
+	assert.Equal(t, 1*time.Second, p.delay(1, 0, 0))
+	assert.Equal(t, 3*time.Second, p.delay(1, 0, 1))
+
+	assert.Equal(t, 30*time.Second, p.delay(1, 30*time.Second, 0.5), "Retry-After wins when longer")
+}
+
+func TestIsRetryableWhisperErr(t *testing.T) {
+	retryable := []error{
+		context.DeadlineExceeded,
+		whisper.ErrNoEndpoint,
+		&whisper.HTTPError{StatusCode: 429},
+		fmt.Errorf("rest: %w", &whisper.HTTPError{StatusCode: 503}),
+		&net.OpError{Op: "dial", Err: errors.New("connection refused")},
+	}
+	for _, err := range retryable {
+		assert.True(t, isRetryableWhisperErr(err), err.Error())
+	}
+	permanent := []error{
+		context.Canceled,
+		&whisper.HTTPError{StatusCode: 400},
+		&net.OpError{Op: "read", Err: errors.New("use of closed network connection")},
+		errors.New("decode audio"),
+	}
+	for _, err := range permanent {
+		assert.False(t, isRetryableWhisperErr(err), err.Error())
+	}
+}
@@ -0,0 +1,115 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"news-scrabber/internal/config"
+)
+
+// Client translates text through an OpenAI-compatible chat completions API
+// (POST {BaseURL}/chat/completions), so a local stand-in server works as well.
+type Client struct {
+	HTTP           *http.Client
+	BaseURL        string
+	APIKey         string
+	Model          string
+	TargetLanguage string
+	enabled        bool
+}
+
+func NewClient(cfg *config.Config) (*Client, error) {
+	to := 60 * time.Second
+	if cfg.OpenAI.TimeoutSec > 0 {
+		to = time.Duration(cfg.OpenAI.TimeoutSec) * time.Second
+	}
+	return &Client{
+		HTTP:           &http.Client{Timeout: to},
+		BaseURL:        cfg.OpenAI.BaseURL,
+		APIKey:         cfg.OpenAI.APIKey,
+		Model:          cfg.Translate.Model,
+		TargetLanguage: cfg.Translate.TargetLanguage,
+		enabled:        cfg.Translate.Enabled && cfg.Translate.TargetLanguage != "" && cfg.OpenAI.BaseURL != "",
+	}, nil
+}
+
+// Enabled reports whether translation is configured.
+func (c *Client) Enabled() bool {
+	return c.enabled
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Translate returns text translated into TargetLanguage. sourceLanguage is an optional
+// ISO code hint; empty lets the model work it out.
+func (c *Client) Translate(ctx context.Context, text, sourceLanguage string) (string, error) {
+	from := "the source language"
+	if sourceLanguage != "" {
+		from = "language code " + sourceLanguage
+	}
+	body, err := json.Marshal(chatRequest{
+		Model: c.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: fmt.Sprintf(
+				"You translate news broadcast transcripts from %s into language code %s. "+
+					"Keep names, numbers and meaning exact. Reply with the translation only.",
+				from, c.TargetLanguage)},
+			{Role: "user", Content: text},
+		},
+		Temperature: 0,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	url := strings.TrimRight(c.BaseURL, "/") + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("translate http %d: %s", resp.StatusCode, string(b))
+	}
+	var out chatResponse
+	if err := json.Unmarshal(b, &out); err != nil {
+		return "", fmt.Errorf("decode chat completion: %w", err)
+	}
+	if len(out.Choices) == 0 {
+		return "", fmt.Errorf("chat completion has no choices")
+	}
+	return strings.TrimSpace(out.Choices[0].Message.Content), nil
+}
@@ -35,6 +35,17 @@ def _load_model(name: str):
     return model
 
 
+def _detect_language(model, path: str):
+    """Detect the spoken language on the first 30 s, like transcribe() does, keeping its probability."""
+    if not model.is_multilingual:
+        return "en", 1.0
+    audio = whisper.pad_or_trim(whisper.load_audio(path))
+    mel = whisper.log_mel_spectrogram(audio, model.dims.n_mels).to(model.device)
+    _, probs = model.detect_language(mel)
+    lang = max(probs, key=probs.get)
+    return lang, float(probs[lang])
+
+
 @app.get("/")
 async def root():
     return {"status": "ok"}
@@ -46,6 +57,7 @@ async def inference(
     model: str = Form(None),
     language: str = Form(None),
     beam_size: int = Form(1),
+    word_timestamps: bool = Form(False),
 ):
     # Normalize model choice; treat "auto"/empty as default model
     chosen = model or os.getenv("WHISPER_MODEL", "base")
@@ -64,13 +76,24 @@ async def inference(
     try:
         device = os.getenv("WHISPER_DEVICE", "cpu")
         model = _load_model(model_name)
+        # a pinned language is used as is; otherwise detect it once and transcribe with it
+        pinned = None if language in (None, "auto", "") else language
+        language_probability = None
+        if pinned is None:
+            pinned, language_probability = _detect_language(model, tmp_path)
         result = model.transcribe(
             tmp_path,
-            language=None if language in (None, "auto", "") else language,
+            language=pinned,
             beam_size=beam_size,
+            word_timestamps=word_timestamps,
             fp16=False if device == "cpu" else True,
         )
-        return JSONResponse({"text": result.get("text", ""), "segments": result.get("segments", [])})
+        return JSONResponse({
+            "text": result.get("text", ""),
+            "segments": result.get("segments", []),
+            "language": result.get("language", pinned),
+            "language_probability": language_probability,
+        })
     finally:
         try:
             os.remove(tmp_path)